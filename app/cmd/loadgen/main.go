@@ -0,0 +1,49 @@
+// Command loadgen drives a running player server and prints latency
+// percentiles and throughput.
+//
+//	loadgen -url http://localhost:5000 -c 32 -d 30s -writes 0.2
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"theinvincible/app/loadgen"
+)
+
+func main() {
+	target := flag.String("url", "http://localhost:5000", "base URL of the player server")
+	concurrency := flag.Int("c", 8, "number of concurrent clients")
+	requests := flag.Int("n", 10000, "total requests to send, ignored when -d is set")
+	duration := flag.Duration("d", 0, "how long to run for")
+	writes := flag.Float64("writes", 0.1, "fraction of requests that record a win")
+	players := flag.Int("players", 100, "number of distinct players")
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "random seed for the request mix")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := &http.Client{
+		Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency},
+		Timeout:   10 * time.Second,
+	}
+	report, err := loadgen.Run(ctx, client, strings.TrimSuffix(*target, "/"), loadgen.Config{
+		Concurrency: *concurrency,
+		Requests:    *requests,
+		Duration:    *duration,
+		WriteRatio:  *writes,
+		Players:     *players,
+		Seed:        *seed,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	report.WriteTo(os.Stdout)
+}
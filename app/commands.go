@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"theinvincible/app/playerclient"
+)
+
+const (
+	defaultHTTPAddr = ":5000"
+	defaultRPCAddr  = "localhost:5001"
+)
+
+// run dispatches to a subcommand. With no arguments the server is started.
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return serveCommand(nil)
+	}
+
+	switch args[0] {
+	case "serve":
+		return serveCommand(args[1:])
+	case "backup":
+		return backupCommand(args[1:], stdout)
+	case "restore":
+		return restoreCommand(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command %q (want serve, backup or restore)", args[0])
+	}
+}
+
+func serveCommand(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	httpAddr := flags.String("http", defaultHTTPAddr, "address to serve HTTP on")
+	rpcAddr := flags.String("rpc", defaultRPCAddr, "address to serve RPC on; it is unauthenticated and can restore every score, so keep it on loopback")
+	corsOrigins := flags.String("cors-origins", "", "comma separated origins allowed to call the HTTP API")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	store := NewInMemoryPlayerStore()
+
+	rpcServer, err := NewRPCServer(store)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", *rpcAddr)
+	if err != nil {
+		return err
+	}
+	go rpcServer.Accept(listener)
+
+	var origins []string
+	if *corsOrigins != "" {
+		origins = strings.Split(*corsOrigins, ",")
+	}
+	server := Chain(NewPlayerServer(store), SecureHeaders, CORS(DefaultCORSConfig(origins...)))
+	return http.ListenAndServe(*httpAddr, server)
+}
+
+// backupCommand snapshots the league of a running server over RPC.
+func backupCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	rpcAddr := flags.String("rpc", defaultRPCAddr, "address of the running server's RPC endpoint")
+	output := flags.String("o", "-", "file to write the snapshot to, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := playerclient.Dial("tcp", *rpcAddr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	remote, err := client.GetLeague()
+	if err != nil {
+		return err
+	}
+	league := make([]Player, len(remote))
+	for i, player := range remote {
+		league[i] = Player{player.Name, player.Wins}
+	}
+	snapshot := NewSnapshot(league, time.Now())
+
+	if *output == "-" {
+		return WriteSnapshot(stdout, snapshot)
+	}
+	if err := writeFileAtomically(*output, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "backed up %d players to %s (%s)\n", len(snapshot.Players), *output, snapshot.Checksum)
+	return nil
+}
+
+// restoreCommand validates a snapshot and loads it into a running server.
+func restoreCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	rpcAddr := flags.String("rpc", defaultRPCAddr, "address of the running server's RPC endpoint")
+	input := flags.String("i", "", "snapshot file to restore")
+	dryRun := flags.Bool("dry-run", false, "only validate the snapshot")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return errors.New("restore: -i is required")
+	}
+
+	f, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	snapshot, err := ReadSnapshot(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *input, err)
+	}
+	if *dryRun {
+		fmt.Fprintf(stdout, "%s is valid: %d players (%s)\n", *input, len(snapshot.Players), snapshot.Checksum)
+		return nil
+	}
+
+	client, err := playerclient.Dial("tcp", *rpcAddr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	league := make([]playerclient.Player, len(snapshot.Players))
+	for i, player := range snapshot.Players {
+		league[i] = playerclient.Player{Name: player.Name, Wins: player.Wins}
+	}
+	restored, err := client.Restore(league)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "restored %d players from %s\n", restored, *input)
+	return nil
+}
+
+// writeFileAtomically writes to a temporary file next to path and renames
+// it into place, so a crash never leaves a half-written backup behind.
+func writeFileAtomically(path string, snapshot Snapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteSnapshot(tmp, snapshot); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// Pinger is implemented by stores that can check they are able to serve
+// requests. It is optional; stores without it are always considered ready.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+const defaultReadyTimeout = time.Second
+
+// buildTime can be set at link time:
+//
+//	go build -ldflags "-X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without it /version falls back to the time of the VCS commit.
+var buildTime string
+
+// BuildInfo is the body of /version.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Modified  bool   `json:"modified"`
+	BuildTime string `json:"buildTime,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{Version: "(devel)", BuildTime: buildTime}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	if bi.Main.Version != "" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// healthzHandler reports that the process is up and serving HTTP.
+func (p *PlayerServer) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "ok")
+}
+
+// readyzHandler reports whether the store answers a ping within readyTimeout.
+func (p *PlayerServer) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if pinger, ok := p.store.(Pinger); ok {
+		ctx, cancel := context.WithTimeout(r.Context(), p.readyTimeout)
+		defer cancel()
+
+		if err := ping(ctx, pinger); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "store not ready: %v", err)
+			return
+		}
+	}
+	fmt.Fprint(w, "ready")
+}
+
+// ping gives up when ctx is done even if the store's Ping ignores ctx. The
+// result channel is buffered so a late Ping can still finish and exit.
+func ping(ctx context.Context, pinger Pinger) error {
+	result := make(chan error, 1)
+	go func() { result <- pinger.Ping(ctx) }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *PlayerServer) versionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(readBuildInfo())
+}
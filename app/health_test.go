@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// StubPingStore is a StubPlayerStore whose Ping returns err, or blocks
+// until the deadline when block is set, or until hang is closed, ignoring
+// the deadline, when hang is set.
+type StubPingStore struct {
+	StubPlayerStore
+	err   error
+	block bool
+	hang  chan struct{}
+}
+
+func (s *StubPingStore) Ping(ctx context.Context) error {
+	if s.hang != nil {
+		<-s.hang
+		return nil
+	}
+	if s.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.err
+}
+
+func TestHealthz(t *testing.T) {
+	server := NewPlayerServer(&StubPingStore{err: errors.New("down")})
+
+	res := httptest.NewRecorder()
+	server.ServeHTTP(res, newGetRequest("/healthz"))
+
+	assertStatus(t, res.Code, http.StatusOK)
+	assertResponseBody(t, res.Body.String(), "ok")
+}
+
+func TestReadyz(t *testing.T) {
+	t.Run("ready when the store answers a ping", func(t *testing.T) {
+		server := NewPlayerServer(NewInMemoryPlayerStore())
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newGetRequest("/readyz"))
+
+		assertStatus(t, res.Code, http.StatusOK)
+	})
+
+	t.Run("ready when the store cannot be pinged", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{})
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newGetRequest("/readyz"))
+
+		assertStatus(t, res.Code, http.StatusOK)
+	})
+
+	t.Run("not ready when the ping fails", func(t *testing.T) {
+		server := NewPlayerServer(&StubPingStore{err: errors.New("connection refused")})
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newGetRequest("/readyz"))
+
+		assertStatus(t, res.Code, http.StatusServiceUnavailable)
+		assertResponseBody(t, res.Body.String(), "store not ready: connection refused")
+	})
+
+	t.Run("not ready when the ping misses the deadline", func(t *testing.T) {
+		server := NewPlayerServer(&StubPingStore{block: true})
+		server.readyTimeout = 10 * time.Millisecond
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newGetRequest("/readyz"))
+
+		assertStatus(t, res.Code, http.StatusServiceUnavailable)
+	})
+
+	t.Run("not ready when the ping ignores the deadline", func(t *testing.T) {
+		hang := make(chan struct{})
+		defer close(hang)
+		server := NewPlayerServer(&StubPingStore{hang: hang})
+		server.readyTimeout = 10 * time.Millisecond
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newGetRequest("/readyz"))
+
+		assertStatus(t, res.Code, http.StatusServiceUnavailable)
+		assertResponseBody(t, res.Body.String(), "store not ready: "+context.DeadlineExceeded.Error())
+	})
+}
+
+func TestVersion(t *testing.T) {
+	server := NewPlayerServer(&StubPlayerStore{})
+
+	res := httptest.NewRecorder()
+	server.ServeHTTP(res, newGetRequest("/version"))
+
+	assertStatus(t, res.Code, http.StatusOK)
+	assertContentType(t, res, "application/json")
+
+	var got BuildInfo
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatalf("unable to parse /version response: %v", err)
+	}
+	if got.Version == "" || got.GoVersion == "" {
+		t.Errorf("expected a version and Go version, got %+v", got)
+	}
+}
+
+func newGetRequest(path string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	return req
+}
@@ -1,17 +1,69 @@
 package main
 
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
 func NewInMemoryPlayerStore() *InMemoryPlayerStore {
-	return &InMemoryPlayerStore{map[string]int{}}
+	return &InMemoryPlayerStore{store: map[string]int{}}
 }
 
+// InMemoryPlayerStore is safe for concurrent use, as the HTTP and RPC
+// servers share a single instance.
 type InMemoryPlayerStore struct {
+	mu    sync.RWMutex
 	store map[string]int
 }
 
 func (i *InMemoryPlayerStore) RecordWin(name string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	i.store[name]++
 }
 
 func (i *InMemoryPlayerStore) GetPlayerScore(name string) int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	return i.store[name]
 }
+
+// GetLeague returns every player, most wins first and ties sorted by name.
+func (i *InMemoryPlayerStore) GetLeague() []Player {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	league := make([]Player, 0, len(i.store))
+	for name, wins := range i.store {
+		league = append(league, Player{name, wins})
+	}
+	sortLeague(league)
+	return league
+}
+
+// Restore replaces every score with those in league.
+func (i *InMemoryPlayerStore) Restore(league []Player) {
+	store := make(map[string]int, len(league))
+	for _, player := range league {
+		store[player.Name] = player.Wins
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.store = store
+}
+
+// Ping always succeeds unless ctx is already done; memory is never unreachable.
+func (i *InMemoryPlayerStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func sortLeague(league []Player) {
+	sort.Slice(league, func(a, b int) bool {
+		if league[a].Wins != league[b].Wins {
+			return league[a].Wins > league[b].Wins
+		}
+		return league[a].Name < league[b].Name
+	})
+}
@@ -0,0 +1,197 @@
+// Package loadgen drives a PlayerServer over HTTP with a mix of score
+// lookups and recorded wins, and reports latency percentiles and throughput.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config describes the load to generate. When Duration is set the run
+// lasts that long, otherwise it stops after Requests requests.
+type Config struct {
+	Concurrency int
+	Requests    int
+	Duration    time.Duration
+	WriteRatio  float64 // fraction of requests that POST a win, 0 to 1
+	Players     int     // number of distinct player names to spread load over
+	Seed        uint64
+}
+
+var ErrInvalidConfig = errors.New("invalid load configuration")
+
+func (c Config) validate() error {
+	switch {
+	case c.Concurrency < 1:
+		return fmt.Errorf("%w: concurrency must be at least 1", ErrInvalidConfig)
+	case c.Requests < 1 && c.Duration <= 0:
+		return fmt.Errorf("%w: need a number of requests or a duration", ErrInvalidConfig)
+	case c.WriteRatio < 0 || c.WriteRatio > 1:
+		return fmt.Errorf("%w: write ratio must be between 0 and 1", ErrInvalidConfig)
+	case c.Players < 1:
+		return fmt.Errorf("%w: need at least one player", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Report summarises a run. Errors counts transport failures and responses
+// the server should not give (anything but 200, 202 or 404).
+type Report struct {
+	Reads   int
+	Writes  int
+	Errors  int
+	Elapsed time.Duration
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+func (r Report) Requests() int {
+	return r.Reads + r.Writes
+}
+
+// Throughput is the number of requests completed per second.
+func (r Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests()) / r.Elapsed.Seconds()
+}
+
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	n, err := fmt.Fprintf(w,
+		"requests   %d (%d reads, %d writes, %d errors)\n"+
+			"elapsed    %v\n"+
+			"throughput %.1f req/s\n"+
+			"latency    p50 %v  p95 %v  p99 %v  max %v\n",
+		r.Requests(), r.Reads, r.Writes, r.Errors,
+		r.Elapsed.Round(time.Millisecond),
+		r.Throughput(),
+		r.P50, r.P95, r.P99, r.Max)
+	return int64(n), err
+}
+
+type sample struct {
+	latency time.Duration
+	write   bool
+	failed  bool
+}
+
+// Run sends requests to the server at baseURL until the configured number
+// of requests is reached, the duration passes or ctx is cancelled.
+func Run(ctx context.Context, client *http.Client, baseURL string, cfg Config) (Report, error) {
+	if err := cfg.validate(); err != nil {
+		return Report{}, err
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		issued  atomic.Int64
+		wg      sync.WaitGroup
+		results = make([][]sample, cfg.Concurrency)
+	)
+
+	start := time.Now()
+	for worker := range cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(cfg.Seed, uint64(worker)))
+
+			for ctx.Err() == nil {
+				if cfg.Duration <= 0 && issued.Add(1) > int64(cfg.Requests) {
+					return
+				}
+				s, ok := send(ctx, client, baseURL, cfg, rng)
+				if !ok {
+					return
+				}
+				results[worker] = append(results[worker], s)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return summarise(slices.Concat(results...), time.Since(start)), nil
+}
+
+// send makes a single request, reporting false if ctx ended while it was in flight.
+func send(ctx context.Context, client *http.Client, baseURL string, cfg Config, rng *rand.Rand) (sample, bool) {
+	player := url.PathEscape(fmt.Sprintf("player-%d", rng.IntN(cfg.Players)))
+	method := http.MethodGet
+	if rng.Float64() < cfg.WriteRatio {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+"/players/"+player, nil)
+	if err != nil {
+		return sample{failed: true}, true
+	}
+
+	began := time.Now()
+	res, err := client.Do(req)
+	s := sample{latency: time.Since(began), write: method == http.MethodPost}
+	if err != nil {
+		if ctx.Err() != nil {
+			return sample{}, false
+		}
+		s.failed = true
+		return s, true
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+	default:
+		s.failed = true
+	}
+	return s, true
+}
+
+func summarise(samples []sample, elapsed time.Duration) Report {
+	report := Report{Elapsed: elapsed}
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		if s.write {
+			report.Writes++
+		} else {
+			report.Reads++
+		}
+		if s.failed {
+			report.Errors++
+		}
+		latencies = append(latencies, s.latency)
+	}
+
+	slices.Sort(latencies)
+	report.P50 = Percentile(latencies, 50)
+	report.P95 = Percentile(latencies, 95)
+	report.P99 = Percentile(latencies, 99)
+	report.Max = Percentile(latencies, 100)
+	return report
+}
+
+// Percentile returns the nearest-rank percentile p of sorted latencies.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
@@ -0,0 +1,127 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var gets, posts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/players/player-") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodPost:
+			posts.Add(1)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			gets.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("sends exactly the requested number of requests", func(t *testing.T) {
+		gets.Store(0)
+		posts.Store(0)
+		cfg := Config{Concurrency: 4, Requests: 200, WriteRatio: 0.5, Players: 10, Seed: 1}
+
+		report, err := Run(context.Background(), server.Client(), server.URL, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Requests() != 200 {
+			t.Errorf("got %d requests want %d", report.Requests(), 200)
+		}
+		if int64(report.Reads) != gets.Load() || int64(report.Writes) != posts.Load() {
+			t.Errorf("report has %d reads and %d writes, server saw %d and %d",
+				report.Reads, report.Writes, gets.Load(), posts.Load())
+		}
+		if report.Writes == 0 || report.Reads == 0 {
+			t.Errorf("expected a mix of reads and writes, got %+v", report)
+		}
+		if report.Errors != 0 {
+			t.Errorf("got %d errors want none", report.Errors)
+		}
+		if report.P50 > report.P95 || report.P95 > report.P99 || report.P99 > report.Max {
+			t.Errorf("percentiles are out of order: %+v", report)
+		}
+	})
+
+	t.Run("a write ratio of 0 only reads", func(t *testing.T) {
+		cfg := Config{Concurrency: 2, Requests: 50, Players: 1}
+
+		report, _ := Run(context.Background(), server.Client(), server.URL, cfg)
+
+		if report.Writes != 0 {
+			t.Errorf("got %d writes want 0", report.Writes)
+		}
+	})
+
+	t.Run("stops after the duration", func(t *testing.T) {
+		cfg := Config{Concurrency: 2, Duration: 50 * time.Millisecond, Players: 1}
+
+		report, err := Run(context.Background(), server.Client(), server.URL, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Elapsed > time.Second {
+			t.Errorf("ran for %v, wanted about %v", report.Elapsed, cfg.Duration)
+		}
+	})
+
+	t.Run("counts unexpected statuses as errors", func(t *testing.T) {
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer broken.Close()
+
+		report, _ := Run(context.Background(), broken.Client(), broken.URL, Config{Concurrency: 1, Requests: 5, Players: 1})
+
+		if report.Errors != 5 {
+			t.Errorf("got %d errors want %d", report.Errors, 5)
+		}
+	})
+
+	t.Run("rejects an invalid config", func(t *testing.T) {
+		_, err := Run(context.Background(), server.Client(), server.URL, Config{Requests: 1, Players: 1})
+
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("got error %v want %v", err, ErrInvalidConfig)
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	cases := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range cases {
+		if got := Percentile(latencies, tt.p); got != tt.want {
+			t.Errorf("p%v: got %v want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("empty input: got %v want 0", got)
+	}
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"log"
-	"net/http"
+	"os"
 )
 
 // type InMemoryPlayerStore struct{}
@@ -14,8 +14,9 @@ import (
 // func (i *InMemoryPlayerStore) RecordWin(name string) {}
 
 func main() {
-	server := &PlayerServer{NewInMemoryPlayerStore()}
-	log.Fatal(http.ListenAndServe(":5000", server))
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
 }
 
 /*
@@ -23,4 +24,9 @@ Separation of Concerns:
 The code separates the concerns of handling HTTP requests (PlayerServer),
 retrieving data (PlayerStore), and running the server (main).
 This makes the code more modular, easier to test, and maintainable.
+
+The RPC server on localhost:5001 is just another way in to the same store,
+which is why both servers are handed the one InMemoryPlayerStore. It has no
+authentication and can restore (so wipe) every score, so it only listens on
+loopback unless -rpc says otherwise.
 */
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	store := StubPlayerStore{map[string]int{"pepple": 20}, nil, nil}
+	server := CORS(DefaultCORSConfig("https://scores.example"))(NewPlayerServer(&store))
+
+	t.Run("allows a simple request from an allowed origin", func(t *testing.T) {
+		req := NewGetScoreRequest("pepple")
+		req.Header.Set("Origin", "https://scores.example")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusOK)
+		assertResponseBody(t, res.Body.String(), "20")
+		assertHeader(t, res, "Access-Control-Allow-Origin", "https://scores.example")
+		assertHeader(t, res, "Vary", "Origin")
+	})
+
+	t.Run("leaves out CORS headers for other origins", func(t *testing.T) {
+		req := NewGetScoreRequest("pepple")
+		req.Header.Set("Origin", "https://evil.example")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusOK)
+		assertHeader(t, res, "Access-Control-Allow-Origin", "")
+	})
+
+	t.Run("answers a preflight request", func(t *testing.T) {
+		req := newPreflightRequest("https://scores.example", http.MethodPost, "content-type")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusNoContent)
+		assertHeader(t, res, "Access-Control-Allow-Origin", "https://scores.example")
+		assertHeader(t, res, "Access-Control-Allow-Methods", "GET, POST")
+		assertHeader(t, res, "Access-Control-Allow-Headers", "Content-Type")
+		assertHeader(t, res, "Access-Control-Max-Age", "600")
+		if len(store.winCalls) != 0 {
+			t.Errorf("preflight reached the store: %v", store.winCalls)
+		}
+	})
+
+	t.Run("refuses a preflight for a method that is not allowed", func(t *testing.T) {
+		req := newPreflightRequest("https://scores.example", http.MethodDelete, "")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusForbidden)
+		assertHeader(t, res, "Access-Control-Allow-Methods", "")
+	})
+
+	t.Run("refuses a preflight for headers that are not allowed", func(t *testing.T) {
+		req := newPreflightRequest("https://scores.example", http.MethodPost, "X-Secret")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusForbidden)
+	})
+
+	t.Run("refuses a preflight from another origin", func(t *testing.T) {
+		req := newPreflightRequest("https://evil.example", http.MethodGet, "")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusForbidden)
+		assertHeader(t, res, "Access-Control-Allow-Origin", "")
+	})
+
+	t.Run("a wildcard allows any origin", func(t *testing.T) {
+		server := CORS(DefaultCORSConfig("*"))(NewPlayerServer(&store))
+		req := NewGetScoreRequest("pepple")
+		req.Header.Set("Origin", "https://anywhere.example")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertHeader(t, res, "Access-Control-Allow-Origin", "https://anywhere.example")
+	})
+}
+
+func TestSecureHeaders(t *testing.T) {
+	store := StubPlayerStore{map[string]int{"pepple": 20}, nil, nil}
+	server := Chain(NewPlayerServer(&store), SecureHeaders, CORS(DefaultCORSConfig("*")))
+
+	res := httptest.NewRecorder()
+	server.ServeHTTP(res, NewGetScoreRequest("pepple"))
+
+	assertStatus(t, res.Code, http.StatusOK)
+	assertHeader(t, res, "Content-Security-Policy", DefaultContentSecurityPolicy)
+	assertHeader(t, res, "X-Content-Type-Options", "nosniff")
+	assertHeader(t, res, "Referrer-Policy", "no-referrer")
+}
+
+func newPreflightRequest(origin, method, headers string) *http.Request {
+	req, _ := http.NewRequest(http.MethodOptions, "/players/pepple", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func assertHeader(t testing.TB, res *httptest.ResponseRecorder, key, want string) {
+	t.Helper()
+	if got := res.Header().Get(key); got != want {
+		t.Errorf("header %s: got %q want %q", key, got, want)
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// openAPIDocument is the subset of an OpenAPI 3 document the tests care about.
+type openAPIDocument struct {
+	Paths map[string]openAPIPathItem `json:"paths"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `json:"parameters"`
+	Get        *openAPIOperation  `json:"get"`
+	Post       *openAPIOperation  `json:"post"`
+	Put        *openAPIOperation  `json:"put"`
+	Delete     *openAPIOperation  `json:"delete"`
+}
+
+type openAPIParameter struct {
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Schema struct {
+		MinLength int    `json:"minLength"`
+		Pattern   string `json:"pattern"`
+	} `json:"schema"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]struct {
+		Content map[string]json.RawMessage `json:"content"`
+	} `json:"responses"`
+}
+
+func (p openAPIPathItem) operations() map[string]*openAPIOperation {
+	ops := map[string]*openAPIOperation{}
+	for method, op := range map[string]*openAPIOperation{
+		http.MethodGet:    p.Get,
+		http.MethodPost:   p.Post,
+		http.MethodPut:    p.Put,
+		http.MethodDelete: p.Delete,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// every request the spec describes, paired with the response code it should produce
+var specCases = []struct {
+	name   string
+	store  PlayerStore
+	req    *http.Request
+	status int
+}{
+	{
+		name:   "score of a known player",
+		store:  &StubPlayerStore{map[string]int{"pepple": 20}, nil, nil},
+		req:    NewGetScoreRequest("pepple"),
+		status: http.StatusOK,
+	},
+	{
+		name:   "score of a missing player",
+		store:  &StubPlayerStore{map[string]int{}, nil, nil},
+		req:    NewGetScoreRequest("apollo"),
+		status: http.StatusNotFound,
+	},
+	{
+		name:   "recording a win",
+		store:  &StubPlayerStore{map[string]int{}, nil, nil},
+		req:    NewPostWinRequest("pepple"),
+		status: http.StatusAccepted,
+	},
+	{
+		name:   "the league table the scoreboard polls",
+		store:  &StubPlayerStore{nil, nil, []Player{{"pepple", 20}}},
+		req:    NewLeagueRequest(),
+		status: http.StatusOK,
+	},
+	{
+		name:   "the scoreboard page",
+		store:  &StubPlayerStore{nil, nil, []Player{{"pepple", 20}}},
+		req:    httptest.NewRequest(http.MethodGet, "/", nil),
+		status: http.StatusOK,
+	},
+	{
+		name:   "a scoreboard asset",
+		store:  &StubPlayerStore{},
+		req:    httptest.NewRequest(http.MethodGet, "/static/scoreboard.css", nil),
+		status: http.StatusOK,
+	},
+	{
+		name:   "a missing scoreboard asset",
+		store:  &StubPlayerStore{},
+		req:    httptest.NewRequest(http.MethodGet, "/static/missing.js", nil),
+		status: http.StatusNotFound,
+	},
+	{
+		name:   "liveness",
+		store:  &StubPlayerStore{},
+		req:    newGetRequest("/healthz"),
+		status: http.StatusOK,
+	},
+	{
+		name:   "a ready store",
+		store:  &StubPingStore{},
+		req:    newGetRequest("/readyz"),
+		status: http.StatusOK,
+	},
+	{
+		name:   "a store that is not ready",
+		store:  &StubPingStore{err: errors.New("down")},
+		req:    newGetRequest("/readyz"),
+		status: http.StatusServiceUnavailable,
+	},
+	{
+		name:   "build information",
+		store:  &StubPlayerStore{},
+		req:    newGetRequest("/version"),
+		status: http.StatusOK,
+	},
+	{
+		name:   "the spec itself",
+		store:  &StubPlayerStore{map[string]int{}, nil, nil},
+		req:    httptest.NewRequest(http.MethodGet, "/openapi.json", nil),
+		status: http.StatusOK,
+	},
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	covered := map[string]bool{}
+
+	for _, tt := range specCases {
+		t.Run(tt.name, func(t *testing.T) {
+			template, item := matchSpecPath(t, spec, tt.req.URL.Path)
+			op, ok := item.operations()[tt.req.Method]
+			if !ok {
+				t.Fatalf("spec does not describe %s %s", tt.req.Method, template)
+			}
+
+			res := httptest.NewRecorder()
+			NewPlayerServer(tt.store).ServeHTTP(res, tt.req)
+
+			assertStatus(t, res.Code, tt.status)
+
+			code := strconv.Itoa(res.Code)
+			response, ok := op.Responses[code]
+			if !ok {
+				t.Fatalf("spec does not list %s for %s %s", code, tt.req.Method, template)
+			}
+			assertSpecContentType(t, response.Content, res.Header().Get("Content-Type"))
+
+			covered[tt.req.Method+" "+template+" "+code] = true
+		})
+	}
+
+	// the other direction: anything in the spec must be exercised above
+	for template, item := range spec.Paths {
+		for method, op := range item.operations() {
+			for code := range op.Responses {
+				if key := method + " " + template + " " + code; !covered[key] {
+					t.Errorf("spec describes %q but no test case produces it", key)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPIUnknownRoutes(t *testing.T) {
+	server := NewPlayerServer(&StubPlayerStore{map[string]int{}, nil, nil})
+
+	t.Run("returns 404 for paths missing from the spec", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/teams/red", nil))
+
+		assertStatus(t, res.Code, http.StatusNotFound)
+	})
+
+	t.Run("returns 405 for methods missing from the spec", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, httptest.NewRequest(http.MethodDelete, "/players/pepple", nil))
+
+		assertStatus(t, res.Code, http.StatusMethodNotAllowed)
+	})
+}
+
+func loadOpenAPISpec(t testing.TB) openAPIDocument {
+	t.Helper()
+
+	var spec openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	if len(spec.Paths) == 0 {
+		t.Fatal("openapi.json describes no paths")
+	}
+	return spec
+}
+
+// matchSpecPath finds the spec path template for path and validates the
+// path parameters against their schemas.
+func matchSpecPath(t testing.TB, spec openAPIDocument, path string) (string, openAPIPathItem) {
+	t.Helper()
+
+	for template, item := range spec.Paths {
+		values, ok := matchTemplate(template, path)
+		if !ok {
+			continue
+		}
+
+		for _, param := range item.Parameters {
+			if param.In != "path" {
+				continue
+			}
+			value := values[param.Name]
+			if len(value) < param.Schema.MinLength {
+				t.Errorf("path parameter %q is shorter than %d", param.Name, param.Schema.MinLength)
+			}
+			if param.Schema.Pattern != "" && !regexp.MustCompile(param.Schema.Pattern).MatchString(value) {
+				t.Errorf("path parameter %q=%q does not match %s", param.Name, value, param.Schema.Pattern)
+			}
+		}
+		return template, item
+	}
+
+	t.Fatalf("no path in the spec matches %s", path)
+	return "", openAPIPathItem{}
+}
+
+// matchTemplate compares path with a template such as /players/{name}
+// segment by segment, returning the values of the {placeholders}.
+func matchTemplate(template, path string) (map[string]string, bool) {
+	want := strings.Split(template, "/")
+	got := strings.Split(path, "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+
+	values := map[string]string{}
+	for i, segment := range want {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			values[strings.Trim(segment, "{}")] = got[i]
+			continue
+		}
+		if segment != got[i] {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+func assertSpecContentType(t testing.TB, content map[string]json.RawMessage, got string) {
+	t.Helper()
+
+	if len(content) == 0 {
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(got)
+	if err != nil {
+		t.Fatalf("response has invalid Content-Type %q: %v", got, err)
+	}
+	if _, ok := content[mediaType]; !ok {
+		t.Errorf("response Content-Type %q is not listed in the spec", mediaType)
+	}
+}
@@ -0,0 +1,70 @@
+// Package playerclient calls the player store exposed by the app over net/rpc.
+//
+// The service has no authentication: anyone who can reach it can record
+// wins or restore, and so wipe, the whole league. The app serves it on
+// localhost only by default, which is its sole protection; do not move it
+// onto a public address without something in front that authenticates.
+package playerclient
+
+import (
+	"io"
+	"net"
+	"net/rpc"
+)
+
+// ServiceName is the name the app registers its player store under.
+const ServiceName = "PlayerStore"
+
+// Player mirrors the app's league entries. gob matches fields by name,
+// so the two types only need to agree on Name and Wins.
+type Player struct {
+	Name string
+	Wins int
+}
+
+// Client is a typed wrapper around an *rpc.Client.
+type Client struct {
+	rpc *rpc.Client
+}
+
+// Dial connects to the RPC server at address.
+func Dial(network, address string) (*Client, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient returns a Client speaking over an existing connection.
+func NewClient(conn io.ReadWriteCloser) *Client {
+	return &Client{rpc.NewClient(conn)}
+}
+
+func (c *Client) RecordWin(name string) error {
+	var accepted bool
+	return c.rpc.Call(ServiceName+".RecordWin", name, &accepted)
+}
+
+func (c *Client) GetPlayerScore(name string) (int, error) {
+	var score int
+	err := c.rpc.Call(ServiceName+".GetPlayerScore", name, &score)
+	return score, err
+}
+
+func (c *Client) GetLeague() ([]Player, error) {
+	var league []Player
+	err := c.rpc.Call(ServiceName+".GetLeague", true, &league)
+	return league, err
+}
+
+// Restore replaces every score on the server with league.
+func (c *Client) Restore(league []Player) (int, error) {
+	var restored int
+	err := c.rpc.Call(ServiceName+".Restore", league, &restored)
+	return restored, err
+}
+
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
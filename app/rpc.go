@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"net/rpc"
+
+	"theinvincible/app/playerclient"
+)
+
+var ErrMissingPlayerName = errors.New("player name must not be empty")
+
+// PlayerRPC exposes a PlayerStore over net/rpc. Each method has the shape
+// net/rpc requires: an argument, a pointer to the reply and an error.
+// Callers are not authenticated; see the playerclient package.
+type PlayerRPC struct {
+	store PlayerStore
+}
+
+func (p *PlayerRPC) RecordWin(name string, accepted *bool) error {
+	if name == "" {
+		return ErrMissingPlayerName
+	}
+	p.store.RecordWin(name)
+	*accepted = true
+	return nil
+}
+
+func (p *PlayerRPC) GetPlayerScore(name string, score *int) error {
+	if name == "" {
+		return ErrMissingPlayerName
+	}
+	*score = p.store.GetPlayerScore(name)
+	return nil
+}
+
+// GetLeague ignores its argument; net/rpc insists on one.
+func (p *PlayerRPC) GetLeague(_ bool, league *[]Player) error {
+	*league = p.store.GetLeague()
+	return nil
+}
+
+// Restore replaces the store's contents with league, replying with the
+// number of players loaded.
+func (p *PlayerRPC) Restore(league []Player, restored *int) error {
+	if err := restoreLeague(p.store, league); err != nil {
+		return err
+	}
+	*restored = len(league)
+	return nil
+}
+
+// NewRPCServer registers store under playerclient.ServiceName. Hand it the
+// same store as NewPlayerServer so both APIs see the same scores.
+func NewRPCServer(store PlayerStore) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName(playerclient.ServiceName, &PlayerRPC{store}); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"theinvincible/app/playerclient"
+)
+
+func TestPlayerRPC(t *testing.T) {
+	store := NewInMemoryPlayerStore()
+	client := newRPCClient(t, store)
+
+	t.Run("records wins and reads the score back", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if err := client.RecordWin("Pepple"); err != nil {
+				t.Fatalf("RecordWin: %v", err)
+			}
+		}
+
+		got, err := client.GetPlayerScore("Pepple")
+		assertNoRPCError(t, err)
+		if got != 3 {
+			t.Errorf("got score %d want %d", got, 3)
+		}
+	})
+
+	t.Run("lists the league", func(t *testing.T) {
+		assertNoRPCError(t, client.RecordWin("Floyd"))
+
+		got, err := client.GetLeague()
+		assertNoRPCError(t, err)
+
+		want := []playerclient.Player{{Name: "Pepple", Wins: 3}, {Name: "Floyd", Wins: 1}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("shares the store with PlayerServer", func(t *testing.T) {
+		server := NewPlayerServer(store)
+		server.ServeHTTP(httptest.NewRecorder(), NewPostWinRequest("Apollo"))
+
+		got, err := client.GetPlayerScore("Apollo")
+		assertNoRPCError(t, err)
+		if got != 1 {
+			t.Errorf("got score %d want %d", got, 1)
+		}
+
+		assertNoRPCError(t, client.RecordWin("Apollo"))
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, NewGetScoreRequest("Apollo"))
+		assertStatus(t, res.Code, http.StatusOK)
+		assertResponseBody(t, res.Body.String(), "2")
+	})
+
+	t.Run("rejects an empty player name", func(t *testing.T) {
+		err := client.RecordWin("")
+		if err == nil || err.Error() != ErrMissingPlayerName.Error() {
+			t.Errorf("got error %v want %v", err, ErrMissingPlayerName)
+		}
+	})
+}
+
+// newRPCClient serves store on a loopback listener and dials it.
+func newRPCClient(t testing.TB, store PlayerStore) *playerclient.Client {
+	t.Helper()
+
+	client, err := playerclient.Dial("tcp", serveRPC(t, store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// serveRPC serves store on a loopback listener and returns its address.
+func serveRPC(t testing.TB, store PlayerStore) string {
+	t.Helper()
+
+	server, err := NewRPCServer(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go server.Accept(listener)
+
+	return listener.Addr().String()
+}
+
+func assertNoRPCError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected RPC error: %v", err)
+	}
+}
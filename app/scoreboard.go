@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+)
+
+//go:embed templates static
+var assets embed.FS
+
+var scoreboardTemplate = template.Must(template.ParseFS(assets, "templates/scoreboard.html"))
+
+// scoreboardRefreshSeconds is how often the page polls /league.
+const scoreboardRefreshSeconds = 5
+
+type scoreboardRow struct {
+	Rank int
+	Player
+}
+
+type scoreboardPage struct {
+	RefreshSeconds int
+	Rows           []scoreboardRow
+}
+
+func newScoreboardPage(league []Player) scoreboardPage {
+	page := scoreboardPage{RefreshSeconds: scoreboardRefreshSeconds}
+	for i, player := range league {
+		page.Rows = append(page.Rows, scoreboardRow{i + 1, player})
+	}
+	return page
+}
+
+func (p *PlayerServer) scoreboardHandler(w http.ResponseWriter, r *http.Request) {
+	// render into a buffer first so a template error can still become a 500
+	var buf bytes.Buffer
+	if err := scoreboardTemplate.Execute(&buf, newScoreboardPage(p.store.GetLeague())); err != nil {
+		http.Error(w, "could not render scoreboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+// leagueHandler is the JSON view of the standings the scoreboard polls.
+func (p *PlayerServer) leagueHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p.store.GetLeague())
+}
+
+func (p *PlayerServer) staticHandler(w http.ResponseWriter, r *http.Request) {
+	http.ServeFileFS(w, r, assets, "static/"+r.PathValue("file"))
+}
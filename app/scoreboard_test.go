@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestScoreboard(t *testing.T) {
+	cases := []struct {
+		name   string
+		league []Player
+	}{
+		{name: "league", league: []Player{{"Pepple", 32}, {"Floyd", 20}, {"<b>Apollo</b>", 14}}},
+		{name: "empty", league: nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			store := StubPlayerStore{nil, nil, tt.league}
+			server := NewPlayerServer(&store)
+
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			res := httptest.NewRecorder()
+			server.ServeHTTP(res, req)
+
+			assertStatus(t, res.Code, http.StatusOK)
+			assertContentType(t, res, "text/html; charset=utf-8")
+			assertGolden(t, filepath.Join("testdata", "scoreboard_"+tt.name+".golden"), res.Body.String())
+		})
+	}
+}
+
+func TestLeague(t *testing.T) {
+	t.Run("it returns the league table as JSON", func(t *testing.T) {
+		wantedLeague := []Player{
+			{"Pepple", 32},
+			{"Floyd", 20},
+			{"Apollo", 14},
+		}
+
+		store := StubPlayerStore{nil, nil, wantedLeague}
+		server := NewPlayerServer(&store)
+
+		req := NewLeagueRequest()
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		got := getLeagueFromResponse(t, res.Body)
+		assertStatus(t, res.Code, http.StatusOK)
+		assertLeague(t, got, wantedLeague)
+		assertContentType(t, res, "application/json")
+	})
+}
+
+func TestScoreboardAssets(t *testing.T) {
+	server := NewPlayerServer(&StubPlayerStore{})
+
+	t.Run("serves the polling script", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/static/scoreboard.js", nil)
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusOK)
+		assertContentType(t, res, "text/javascript; charset=utf-8")
+	})
+
+	t.Run("does not serve files outside static", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/static/..%2ftemplates%2fscoreboard.html", nil)
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, req)
+
+		if res.Code == http.StatusOK {
+			t.Errorf("served a template through /static/")
+		}
+	})
+
+	t.Run("only the root path is the scoreboard", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/nope", nil)
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, req)
+
+		assertStatus(t, res.Code, http.StatusNotFound)
+	})
+}
+
+func assertGolden(t testing.TB, path, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run go test -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func NewLeagueRequest() *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, "/league", nil)
+	return req
+}
+
+func getLeagueFromResponse(t testing.TB, body io.Reader) (league []Player) {
+	t.Helper()
+	err := json.NewDecoder(body).Decode(&league)
+
+	if err != nil {
+		t.Fatalf("unable to parse response from server %q into slice of Player, '%v'", body, err)
+	}
+	return
+}
@@ -1,33 +1,59 @@
 package main
 
 import (
+	_ "embed"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 )
 
 type PlayerStore interface {
 	GetPlayerScore(name string) int
 	RecordWin(name string)
+	GetLeague() []Player
 }
 
+// Player stores a name with a number of wins
+type Player struct {
+	Name string `json:"name"`
+	Wins int    `json:"wins"`
+}
+
+// PlayerServer embeds an http.Handler (the router) so every route is
+// registered in one place by NewPlayerServer.
 type PlayerServer struct {
-	store PlayerStore
+	store        PlayerStore
+	readyTimeout time.Duration
+	http.Handler
 }
 
-func (p *PlayerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	player := strings.TrimPrefix(r.URL.Path, "/players/")
+//go:embed openapi.json
+var openAPISpec []byte
 
-	switch r.Method {
-	case http.MethodPost:
-		p.processWin(w, player)
-	case http.MethodGet:
-		p.showScore(w, player)
-	}
+func NewPlayerServer(store PlayerStore) *PlayerServer {
+	p := new(PlayerServer)
+	p.store = store
+	p.readyTimeout = defaultReadyTimeout
+
+	router := http.NewServeMux()
+	router.Handle("GET /players/{name}", http.HandlerFunc(p.showScore))
+	router.Handle("POST /players/{name}", http.HandlerFunc(p.processWin))
+	router.Handle("GET /openapi.json", http.HandlerFunc(p.openAPIHandler))
+	router.Handle("GET /healthz", http.HandlerFunc(p.healthzHandler))
+	router.Handle("GET /readyz", http.HandlerFunc(p.readyzHandler))
+	router.Handle("GET /version", http.HandlerFunc(p.versionHandler))
+	router.Handle("GET /{$}", http.HandlerFunc(p.scoreboardHandler))
+	router.Handle("GET /league", http.HandlerFunc(p.leagueHandler))
+	router.Handle("GET /static/{file}", http.HandlerFunc(p.staticHandler))
+
+	p.Handler = router
+	return p
 }
 
-func (p *PlayerServer) showScore(w http.ResponseWriter, player string) {
+func (p *PlayerServer) showScore(w http.ResponseWriter, r *http.Request) {
+	player := r.PathValue("name")
 
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	score := p.store.GetPlayerScore(player)
 	if score == 0 {
 		w.WriteHeader(http.StatusNotFound)
@@ -36,13 +62,24 @@ func (p *PlayerServer) showScore(w http.ResponseWriter, player string) {
 	fmt.Fprint(w, score)
 }
 
-func (p *PlayerServer) processWin(w http.ResponseWriter, player string) {
+func (p *PlayerServer) processWin(w http.ResponseWriter, r *http.Request) {
+	player := r.PathValue("name")
+
 	p.store.RecordWin(player)
 	w.WriteHeader(http.StatusAccepted)
 }
 
+func (p *PlayerServer) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
 /*
 The intent behind our code is clearer now due to the introduction of the store.
  We're telling the reader that because we have this data in a PlayerStore that
  when you use it with a PlayerServer you should get the following responses.
+
+Routing is delegated to an http.ServeMux. Since Go 1.22 a pattern can carry
+the method and wildcards ("GET /players/{name}"), so the mux answers 404 for
+unknown paths and 405 for unsupported methods without any code of ours.
 */
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"theinvincible/app/loadgen"
+)
+
+// BenchmarkPlayerServer drives a real HTTP server with loadgen, once per
+// store and read/write mix. Add a store here to compare it with the others:
+//
+//	go test -bench PlayerServer -run ^$ ./app
+func BenchmarkPlayerServer(b *testing.B) {
+	stores := []struct {
+		name  string
+		store func() PlayerStore
+	}{
+		{"in-memory", func() PlayerStore { return NewInMemoryPlayerStore() }},
+	}
+	writeRatios := []float64{0, 0.1, 0.5, 1}
+
+	for _, s := range stores {
+		for _, writes := range writeRatios {
+			b.Run(fmt.Sprintf("%s/writes=%.0f%%", s.name, writes*100), func(b *testing.B) {
+				server := httptest.NewServer(NewPlayerServer(s.store()))
+				defer server.Close()
+
+				cfg := loadgen.Config{
+					Concurrency: 8,
+					Requests:    b.N,
+					WriteRatio:  writes,
+					Players:     100,
+					Seed:        1,
+				}
+
+				b.ResetTimer()
+				report, err := loadgen.Run(context.Background(), server.Client(), server.URL, cfg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if report.Errors > 0 {
+					b.Fatalf("%d requests failed", report.Errors)
+				}
+
+				b.ReportMetric(report.Throughput(), "req/s")
+				b.ReportMetric(float64(report.P50.Microseconds()), "p50-µs")
+				b.ReportMetric(float64(report.P95.Microseconds()), "p95-µs")
+				b.ReportMetric(float64(report.P99.Microseconds()), "p99-µs")
+			})
+		}
+	}
+}
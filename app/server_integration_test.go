@@ -8,16 +8,25 @@ import (
 
 func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 	store := NewInMemoryPlayerStore()
-	server := PlayerServer{store}
+	server := NewPlayerServer(store)
 	player := "Pepple"
 
 	server.ServeHTTP(httptest.NewRecorder(), NewPostWinRequest(player))
 	server.ServeHTTP(httptest.NewRecorder(), NewPostWinRequest(player))
 	server.ServeHTTP(httptest.NewRecorder(), NewPostWinRequest(player))
 
-	response := httptest.NewRecorder()
-	server.ServeHTTP(response, NewPostWinRequest(player))
-	assertStatus(t, response.Code, http.StatusOK)
+	t.Run("get score", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, NewGetScoreRequest(player))
+		assertStatus(t, response.Code, http.StatusOK)
 
-	assertResponseBody(t, response.Body.String(), "3")
+		assertResponseBody(t, response.Body.String(), "3")
+	})
+
+	t.Run("get league", func(t *testing.T) {
+		want := []Player{
+			{"Pepple", 3},
+		}
+		assertLeague(t, store.GetLeague(), want)
+	})
 }
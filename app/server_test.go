@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
 type StubPlayerStore struct {
 	scores   map[string]int
 	winCalls []string
+	league   []Player
 }
 
 func (s *StubPlayerStore) GetPlayerScore(name string) int {
@@ -21,6 +23,10 @@ func (s *StubPlayerStore) RecordWin(name string) {
 	s.winCalls = append(s.winCalls, name)
 }
 
+func (s *StubPlayerStore) GetLeague() []Player {
+	return s.league
+}
+
 func TestGetPlayer(t *testing.T) {
 	store := StubPlayerStore{
 		map[string]int{
@@ -28,8 +34,9 @@ func TestGetPlayer(t *testing.T) {
 			"floyd":  10,
 		},
 		nil,
+		nil,
 	}
-	server := &PlayerServer{&store}
+	server := NewPlayerServer(&store)
 	t.Run("returns Pepple's score", func(t *testing.T) {
 		req := NewGetScoreRequest("pepple")
 		res := httptest.NewRecorder()
@@ -65,8 +72,9 @@ func TestStoreWins(t *testing.T) {
 	store := StubPlayerStore{
 		map[string]int{},
 		nil,
+		nil,
 	}
-	server := &PlayerServer{&store}
+	server := NewPlayerServer(&store)
 
 	t.Run("it returns accepted on POST", func(t *testing.T) {
 		player := "pepple"
@@ -103,7 +111,7 @@ func NewGetScoreRequest(name string) *http.Request {
 func assertStatus(t testing.TB, got, want int) {
 	t.Helper()
 	if got != want {
-		t.Errorf("response body is wrong, got %q want %q", got, want)
+		t.Errorf("did not get correct status, got %d want %d", got, want)
 	}
 
 }
@@ -112,3 +120,17 @@ func NewPostWinRequest(name string) *http.Request {
 	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/players/%s", name), nil)
 	return req
 }
+
+func assertLeague(t testing.TB, got, want []Player) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func assertContentType(t testing.TB, res *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if res.Result().Header.Get("content-type") != want {
+		t.Errorf("response did not have content-type of %s, got %v", want, res.Result().Header)
+	}
+}
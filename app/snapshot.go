@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// snapshotVersion 2 extended the checksum from the players to the
+// version and creation time as well.
+const snapshotVersion = 2
+
+var (
+	ErrChecksumMismatch   = errors.New("snapshot checksum does not match its contents")
+	ErrUnsupportedVersion = errors.New("snapshot version is not supported")
+	ErrRestoreUnsupported = errors.New("player store cannot be restored")
+)
+
+// Snapshot is a point-in-time copy of a league. The checksum covers the
+// version, creation time and players, so a truncated or hand-edited backup
+// is refused on restore.
+type Snapshot struct {
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	Players  []Player  `json:"players"`
+	Checksum string    `json:"checksum"`
+}
+
+// Restorer is implemented by stores that can replace their contents
+// wholesale. It is optional: PlayerStore only needs to record wins.
+type Restorer interface {
+	Restore(league []Player)
+}
+
+// TakeSnapshot copies the league of store. A single GetLeague call is
+// used so the copy is as consistent as the store's own locking.
+func TakeSnapshot(store PlayerStore, now time.Time) Snapshot {
+	return NewSnapshot(store.GetLeague(), now)
+}
+
+func NewSnapshot(league []Player, now time.Time) Snapshot {
+	players := append([]Player{}, league...)
+	sortLeague(players)
+	return Snapshot{
+		Version: snapshotVersion,
+		Created: now.UTC(),
+		Players: players,
+	}.withChecksum()
+}
+
+func (s Snapshot) withChecksum() Snapshot {
+	s.Checksum = s.checksum()
+	return s
+}
+
+// Validate checks the version, checksum and every player in the snapshot.
+func (s Snapshot) Validate() error {
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, s.Version)
+	}
+	if s.Checksum != s.checksum() {
+		return ErrChecksumMismatch
+	}
+	return validateLeague(s.Players)
+}
+
+func WriteSnapshot(w io.Writer, s Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// ReadSnapshot decodes a snapshot and validates it before returning.
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return Snapshot{}, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return Snapshot{}, err
+	}
+	return s, nil
+}
+
+// RestoreSnapshot validates s and loads it into store, replacing what was there.
+func RestoreSnapshot(store PlayerStore, s Snapshot) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	return restoreLeague(store, s.Players)
+}
+
+func restoreLeague(store PlayerStore, league []Player) error {
+	restorer, ok := store.(Restorer)
+	if !ok {
+		return ErrRestoreUnsupported
+	}
+	if err := validateLeague(league); err != nil {
+		return err
+	}
+	restorer.Restore(league)
+	return nil
+}
+
+func validateLeague(league []Player) error {
+	seen := map[string]bool{}
+	for _, player := range league {
+		switch {
+		case player.Name == "":
+			return ErrMissingPlayerName
+		case player.Wins < 0:
+			return fmt.Errorf("player %q has negative wins %d", player.Name, player.Wins)
+		case seen[player.Name]:
+			return fmt.Errorf("player %q appears more than once", player.Name)
+		}
+		seen[player.Name] = true
+	}
+	return nil
+}
+
+func (s Snapshot) checksum() string {
+	s.Checksum = ""
+	// json.Marshal of a Snapshot cannot fail
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var snapshotTime = time.Date(2024, time.September, 5, 12, 0, 0, 0, time.UTC)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	original := NewInMemoryPlayerStore()
+	for _, name := range []string{"Pepple", "Pepple", "Floyd", "Pepple", "Apollo", "Floyd"} {
+		original.RecordWin(name)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, TakeSnapshot(original, snapshotTime)); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := ReadSnapshot(&buf)
+	assertNoSnapshotError(t, err)
+
+	restored := NewInMemoryPlayerStore()
+	restored.RecordWin("Stale")
+	assertNoSnapshotError(t, RestoreSnapshot(restored, snapshot))
+
+	assertLeague(t, restored.GetLeague(), original.GetLeague())
+	if !snapshot.Created.Equal(snapshotTime) {
+		t.Errorf("got created %v want %v", snapshot.Created, snapshotTime)
+	}
+}
+
+func TestSnapshotValidation(t *testing.T) {
+	valid := func() Snapshot {
+		return NewSnapshot([]Player{{"Pepple", 3}, {"Floyd", 1}}, snapshotTime)
+	}
+
+	t.Run("rejects tampered players", func(t *testing.T) {
+		snapshot := valid()
+		snapshot.Players[0].Wins = 300
+
+		assertSnapshotError(t, snapshot.Validate(), ErrChecksumMismatch)
+	})
+
+	t.Run("rejects a tampered file", func(t *testing.T) {
+		var buf bytes.Buffer
+		WriteSnapshot(&buf, valid())
+		tampered := strings.Replace(buf.String(), `"wins": 3`, `"wins": 4`, 1)
+
+		_, err := ReadSnapshot(strings.NewReader(tampered))
+		assertSnapshotError(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("rejects a tampered creation time", func(t *testing.T) {
+		var buf bytes.Buffer
+		WriteSnapshot(&buf, valid())
+		tampered := strings.Replace(buf.String(), "2024-09-05", "2023-09-05", 1)
+
+		_, err := ReadSnapshot(strings.NewReader(tampered))
+		assertSnapshotError(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("rejects unknown versions", func(t *testing.T) {
+		snapshot := valid()
+		snapshot.Version = snapshotVersion + 1
+
+		assertSnapshotError(t, snapshot.Validate(), ErrUnsupportedVersion)
+	})
+
+	t.Run("rejects duplicate players", func(t *testing.T) {
+		snapshot := NewSnapshot([]Player{{"Pepple", 3}, {"Pepple", 1}}, snapshotTime)
+
+		if err := snapshot.Validate(); err == nil {
+			t.Error("expected an error for a duplicated player")
+		}
+	})
+
+	t.Run("stores without Restore are refused", func(t *testing.T) {
+		store := &StubPlayerStore{}
+
+		assertSnapshotError(t, RestoreSnapshot(store, valid()), ErrRestoreUnsupported)
+	})
+}
+
+func TestBackupAndRestoreCommands(t *testing.T) {
+	live := NewInMemoryPlayerStore()
+	live.RecordWin("Pepple")
+	live.RecordWin("Pepple")
+	live.RecordWin("Floyd")
+	addr := serveRPC(t, live)
+
+	path := filepath.Join(t.TempDir(), "players.json")
+	var out bytes.Buffer
+
+	err := run([]string{"backup", "-rpc", addr, "-o", path}, &out)
+	assertNoSnapshotError(t, err)
+
+	want := live.GetLeague()
+	live.RecordWin("Apollo")
+
+	err = run([]string{"restore", "-rpc", addr, "-i", path}, &out)
+	assertNoSnapshotError(t, err)
+
+	assertLeague(t, live.GetLeague(), want)
+}
+
+func assertNoSnapshotError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func assertSnapshotError(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
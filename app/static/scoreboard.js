@@ -0,0 +1,45 @@
+// Polls the league JSON and redraws the table body. Cells are filled with
+// textContent so player names are never interpreted as HTML.
+(function () {
+	"use strict";
+
+	var table = document.getElementById("league");
+	var source = table.dataset.source;
+	var refresh = parseInt(table.dataset.refresh, 10) * 1000;
+
+	function cell(row, text) {
+		var td = document.createElement("td");
+		td.textContent = text;
+		row.appendChild(td);
+		return td;
+	}
+
+	function render(league) {
+		var tbody = document.createElement("tbody");
+		league.forEach(function (player, i) {
+			var row = document.createElement("tr");
+			cell(row, i + 1);
+			cell(row, player.name);
+			cell(row, player.wins);
+			tbody.appendChild(row);
+		});
+		if (league.length === 0) {
+			var row = document.createElement("tr");
+			cell(row, "No wins recorded yet.").colSpan = 3;
+			tbody.appendChild(row);
+		}
+		table.replaceChild(tbody, table.tBodies[0]);
+	}
+
+	function poll() {
+		fetch(source, { headers: { Accept: "application/json" } })
+			.then(function (res) { return res.json(); })
+			.then(render)
+			.catch(function () {})
+			.then(function () { setTimeout(poll, refresh); });
+	}
+
+	if (refresh > 0) {
+		setTimeout(poll, refresh);
+	}
+})();
@@ -0,0 +1,151 @@
+package maps
+
+import (
+	"fmt"
+	"strings"
+)
+
+type OpKind int
+
+// OpKind names an operation, both in a batch and in the errors returned
+// by Dictionary and its relatives.
+const (
+	OpAdd OpKind = iota
+	OpUpdate
+	OpDelete
+	OpSearch
+	OpRemoveSense
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpAdd:
+		return "add"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	case OpSearch:
+		return "search"
+	case OpRemoveSense:
+		return "remove sense"
+	}
+	return fmt.Sprintf("OpKind(%d)", int(k))
+}
+
+// Op is a single change to apply as part of a batch.
+type Op struct {
+	Kind       OpKind
+	Word       string
+	Definition string
+}
+
+func AddOp(word, definition string) Op {
+	return Op{OpAdd, word, definition}
+}
+
+func UpdateOp(word, definition string) Op {
+	return Op{OpUpdate, word, definition}
+}
+
+func DeleteOp(word string) Op {
+	return Op{Kind: OpDelete, Word: word}
+}
+
+// Conflict is an operation in a batch that could not be applied.
+type Conflict struct {
+	Index int // position of the operation in the batch
+	Op    OpKind
+	Word  string
+	Err   error
+}
+
+func (c Conflict) Error() string {
+	return fmt.Sprintf("%s %q: %v", c.Op, c.Word, c.Err)
+}
+
+func (c Conflict) Unwrap() error {
+	return c.Err
+}
+
+// BatchError lists every conflicting operation of a failed batch. It
+// matches each conflict's error with errors.Is, e.g. ErrWordExists.
+type BatchError struct {
+	Conflicts []Conflict
+}
+
+func (e *BatchError) Error() string {
+	lines := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		lines[i] = c.Error()
+	}
+	return fmt.Sprintf("batch failed with %d conflicts: %s", len(e.Conflicts), strings.Join(lines, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		errs[i] = c
+	}
+	return errs
+}
+
+// Batch applies ops in order, all or nothing. Each operation sees the
+// effect of the ones before it, so adding a word and then updating it in
+// the same batch works. If any operation conflicts the dictionary is left
+// untouched and a *BatchError listing every conflict is returned.
+func (d Dictionary) Batch(ops ...Op) error {
+	// staged holds the pending value of every word the batch touches;
+	// a nil value means the word is deleted.
+	staged := map[string]*string{}
+	lookup := func(word string) bool {
+		if value, ok := staged[word]; ok {
+			return value != nil
+		}
+		_, ok := d[word]
+		return ok
+	}
+
+	var conflicts []Conflict
+	for i, op := range ops {
+		var err error
+		switch op.Kind {
+		case OpAdd:
+			if lookup(op.Word) {
+				err = ErrWordExists
+			}
+		case OpUpdate:
+			if !lookup(op.Word) {
+				err = ErrWordDoesNotExists
+			}
+		case OpDelete:
+		default:
+			err = fmt.Errorf("unknown operation %v", op.Kind)
+		}
+
+		if err != nil {
+			conflicts = append(conflicts, Conflict{i, op.Kind, op.Word, err})
+			continue
+		}
+
+		if op.Kind == OpDelete {
+			staged[op.Word] = nil
+		} else {
+			definition := op.Definition
+			staged[op.Word] = &definition
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &BatchError{conflicts}
+	}
+
+	for word, definition := range staged {
+		if definition == nil {
+			delete(d, word)
+		} else {
+			d[word] = *definition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package maps
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	t.Run("applies every operation", func(t *testing.T) {
+		dictionary := Dictionary{"old": "stale", "gone": "to be deleted"}
+
+		err := dictionary.Batch(
+			AddOp("test", "this is just a test"),
+			UpdateOp("old", "fresh"),
+			DeleteOp("gone"),
+		)
+
+		assertError(t, err, nil)
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test", "old": "fresh"})
+	})
+
+	t.Run("later operations see earlier ones", func(t *testing.T) {
+		dictionary := Dictionary{}
+
+		err := dictionary.Batch(
+			AddOp("test", "first"),
+			UpdateOp("test", "second"),
+			DeleteOp("test"),
+			AddOp("test", "third"),
+		)
+
+		assertError(t, err, nil)
+		assertDictionary(t, dictionary, Dictionary{"test": "third"})
+	})
+
+	t.Run("applies nothing when one operation conflicts", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+
+		err := dictionary.Batch(
+			AddOp("new", "a new word"),
+			AddOp("test", "clash"),
+		)
+
+		if !errors.Is(err, ErrWordExists) {
+			t.Errorf("got error %v want it to match %v", err, ErrWordExists)
+		}
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test"})
+	})
+
+	t.Run("lists every conflicting word", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+
+		err := dictionary.Batch(
+			AddOp("test", "clash"),
+			AddOp("fine", "ok"),
+			UpdateOp("missing", "nope"),
+			AddOp("fine", "added twice"),
+		)
+
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("got %v want a *BatchError", err)
+		}
+
+		want := []Conflict{
+			{Index: 0, Op: OpAdd, Word: "test", Err: ErrWordExists},
+			{Index: 2, Op: OpUpdate, Word: "missing", Err: ErrWordDoesNotExists},
+			{Index: 3, Op: OpAdd, Word: "fine", Err: ErrWordExists},
+		}
+		if !reflect.DeepEqual(batchErr.Conflicts, want) {
+			t.Errorf("got conflicts %v want %v", batchErr.Conflicts, want)
+		}
+		if !errors.Is(err, ErrWordDoesNotExists) {
+			t.Errorf("expected %v to match %v", err, ErrWordDoesNotExists)
+		}
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test"})
+	})
+
+	t.Run("an empty batch is a no-op", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+
+		assertError(t, dictionary.Batch(), nil)
+		assertDictionary(t, dictionary, Dictionary{"test": "this is just a test"})
+	})
+}
+
+func assertDictionary(t testing.TB, got, want Dictionary) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got dictionary %v want %v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package maps
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Record is one word as read from or written to a file. Line is where the
+// word was found when decoding; codecs without lines count records instead.
+type Record struct {
+	Word       string
+	Definition string
+	Line       int
+}
+
+// Codec converts records to and from a file format. Decode returns the
+// records in file order, duplicates included, so Load can report them.
+type Codec interface {
+	Encode(w io.Writer, records []Record) error
+	Decode(r io.Reader) ([]Record, error)
+}
+
+// records returns the dictionary's words sorted, so saves are reproducible.
+func (d Dictionary) records() []Record {
+	records := make([]Record, 0, len(d))
+	for word, definition := range d {
+		records = append(records, Record{Word: word, Definition: definition})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Word < records[j].Word })
+	return records
+}
+
+// JSONCodec stores the dictionary as a single JSON object of word to definition.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(w io.Writer, records []Record) error {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, rec := range records {
+		word, _ := json.Marshal(rec.Word)
+		definition, _ := json.Marshal(rec.Definition)
+		fmt.Fprintf(&buf, "  %s: %s", word, definition)
+		if i < len(records)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("}\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// Decode walks the object token by token, as unmarshalling into a map
+// would silently keep only the last of any duplicated words.
+func (JSONCodec) Decode(r io.Reader) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	lineAt := func(offset int64) int {
+		return bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("line %d: expected a JSON object of words", lineAt(dec.InputOffset()))
+	}
+
+	var records []Record
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineAt(dec.InputOffset()), err)
+		}
+		word := tok.(string) // object keys are always strings
+		line := lineAt(dec.InputOffset())
+
+		var definition string
+		if err := dec.Decode(&definition); err != nil {
+			return nil, fmt.Errorf("line %d: definition of %q: %w", line, word, err)
+		}
+		records = append(records, Record{word, definition, line})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineAt(dec.InputOffset()), err)
+	}
+	return records, nil
+}
+
+var csvHeader = []string{"word", "definition"}
+
+// CSVCodec stores one word per row under a "word,definition" header.
+type CSVCodec struct{}
+
+func (CSVCodec) Encode(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, rec := range records {
+		cw.Write([]string{rec.Word, rec.Definition})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (CSVCodec) Decode(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	var records []Record
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if line == 1 && row[0] == csvHeader[0] && row[1] == csvHeader[1] {
+			continue
+		}
+		records = append(records, Record{row[0], row[1], line})
+	}
+}
+
+// GobCodec stores the records in encoding/gob. Gob has no lines, so
+// decoded records are numbered from 1 in the order they were written.
+type GobCodec struct{}
+
+type gobRecord struct {
+	Word       string
+	Definition string
+}
+
+func (GobCodec) Encode(w io.Writer, records []Record) error {
+	entries := make([]gobRecord, len(records))
+	for i, rec := range records {
+		entries[i] = gobRecord{rec.Word, rec.Definition}
+	}
+	return gob.NewEncoder(w).Encode(entries)
+}
+
+func (GobCodec) Decode(r io.Reader) ([]Record, error) {
+	var entries []gobRecord
+	if err := gob.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	records := make([]Record, len(entries))
+	for i, e := range entries {
+		records[i] = Record{e.Word, e.Definition, i + 1}
+	}
+	return records, nil
+}
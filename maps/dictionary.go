@@ -0,0 +1,104 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Dictionary map[string]string
+type DictionaryErr string
+
+const (
+	ErrWordExists        = DictionaryErr("cannot add word because it already exists")
+	ErrNotFound          = DictionaryErr("could not find the word you are looking for")
+	ErrWordDoesNotExists = DictionaryErr("cannot update word because it does not exist")
+)
+
+// WordError records the operation and word behind a DictionaryErr. It
+// unwraps to the DictionaryErr, so errors.Is(err, ErrNotFound) still works.
+// A missed search may carry Suggestions of similar words.
+type WordError struct {
+	Op          OpKind
+	Word        string
+	Err         error
+	Suggestions []string
+}
+
+func (e *WordError) Error() string {
+	msg := fmt.Sprintf("%s %q: %v", e.Op, e.Word, e.Err)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", msg, strings.Join(quoted, " or "))
+}
+
+func (e *WordError) Unwrap() error {
+	return e.Err
+}
+
+// Search looks up word. A miss returns a *WordError matching ErrNotFound,
+// suggesting similar words when there are any.
+func (d Dictionary) Search(word string) (string, error) {
+	definition, err := d.search(word)
+	if err != nil {
+		return "", notFound(NewIndex(d), word)
+	}
+	return definition, nil
+}
+
+// search is Search without the suggestions, for Add and Update which only
+// need to know whether the word exists.
+func (d Dictionary) search(word string) (string, error) {
+	definition, ok := d[word] // The second value is a boolean which indicates if the key was found successfully.
+	if !ok {
+		return "", &WordError{Op: OpSearch, Word: word, Err: ErrNotFound}
+	}
+	return definition, nil
+}
+
+func (d Dictionary) Add(word, definition string) error {
+	_, err := d.search(word)
+
+	// errors.Is rather than == so a wrapped ErrNotFound still counts as a miss
+	switch {
+	case errors.Is(err, ErrNotFound):
+		d[word] = definition
+	case err == nil:
+		return &WordError{Op: OpAdd, Word: word, Err: ErrWordExists}
+	default:
+		return err
+	}
+	return nil
+}
+
+// DictionaryErr implemenets the error interface
+func (e DictionaryErr) Error() string {
+	return string(e)
+}
+
+func (d Dictionary) Update(word, definition string) error {
+	_, err := d.search(word)
+
+	// switching between different possiblities of err
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return &WordError{Op: OpUpdate, Word: word, Err: ErrWordDoesNotExists}
+	case err == nil:
+		d[word] = definition
+	default:
+		return err
+	}
+
+	return nil
+}
+
+func (d Dictionary) Delete(word string) {
+	delete(d, word) //Go's built-in function to delete a map entry
+
+}
@@ -1,18 +1,10 @@
-package main
+package maps
 
 import (
+	"errors"
 	"testing"
 )
 
-type Dictionary map[string]string
-type DictionaryErr string
-
-const (
-	ErrWordExists        = DictionaryErr("cannot add word because it already exists")
-	ErrNotFound          = DictionaryErr("could not find the word you are looking for")
-	ErrWordDoesNotExists = DictionaryErr("cannot update word because it does not exist")
-)
-
 func TestSearch(t *testing.T) {
 	dictionary := Dictionary{"test": "this is just a test"} //instantiating a map
 
@@ -91,52 +83,36 @@ func TestDelete(t *testing.T) {
 	assertError(t, err, ErrNotFound)
 }
 
-func (d Dictionary) Search(word string) (string, error) {
-	definition, ok := d[word] // The second value is a boolean which indicates if the key was found successfully.
-	if !ok {
-		return "", ErrNotFound
+func TestWordError(t *testing.T) {
+	dictionary := Dictionary{"test": "this is just a test"}
+
+	cases := []struct {
+		name string
+		err  error
+		want WordError
+	}{
+		{"search", searchErr(dictionary, "unknown"), WordError{Op: OpSearch, Word: "unknown", Err: ErrNotFound}},
+		{"add", dictionary.Add("test", "new test"), WordError{Op: OpAdd, Word: "test", Err: ErrWordExists}},
+		{"update", dictionary.Update("unknown", "new test"), WordError{Op: OpUpdate, Word: "unknown", Err: ErrWordDoesNotExists}},
 	}
-	return definition, nil
-}
 
-func (d Dictionary) Add(word, definition string) error {
-	_, err := d.Search(word)
-
-	switch err {
-	case ErrNotFound:
-		d[word] = definition
-	case nil:
-		return ErrWordExists
-	default:
-		return err
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *WordError
+			if !errors.As(tt.err, &got) {
+				t.Fatalf("got %v want a *WordError", tt.err)
+			}
+			if got.Op != tt.want.Op || got.Word != tt.want.Word || got.Err != tt.want.Err {
+				t.Errorf("got %+v want %+v", *got, tt.want)
+			}
+			assertError(t, tt.err, tt.want.Err)
+		})
 	}
-	return nil
-}
-
-// DictionaryErr implemenets the error interface
-func (e DictionaryErr) Error() string {
-	return string(e)
 }
 
-func (d Dictionary) Update(word, definition string) error {
+func searchErr(d Dictionary, word string) error {
 	_, err := d.Search(word)
-
-	// switching between different possiblities of err
-	switch err {
-	case ErrNotFound:
-		return ErrWordDoesNotExists
-	case nil:
-		d[word] = definition
-	default:
-		return err
-	}
-
-	return nil
-}
-
-func (d Dictionary) Delete(word string) {
-	delete(d, word) //Go's built-in function to delete a map entry
-
+	return err
 }
 
 // -----------------------------------Helper Functions-----------------------------------
@@ -151,7 +127,7 @@ func assertStrings(t testing.TB, got, want string) {
 func assertError(t testing.TB, got, want error) {
 	t.Helper()
 
-	if got != want {
+	if !errors.Is(got, want) {
 		t.Errorf("got error %q want %q", got, want)
 	}
 }
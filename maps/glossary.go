@@ -0,0 +1,174 @@
+package maps
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	ErrNoSenses      = DictionaryErr("cannot add an entry without any senses")
+	ErrSenseNotFound = DictionaryErr("could not find that sense of the word")
+)
+
+// Sense is one meaning of a word.
+type Sense struct {
+	Definition   string
+	PartOfSpeech string
+	Examples     []string
+	Synonyms     []string
+}
+
+// Entry is everything a Glossary knows about a word. Modified is set
+// whenever any of its senses change.
+type Entry struct {
+	Word     string
+	Senses   []Sense
+	Modified time.Time
+}
+
+// clone copies the slices so callers cannot change a stored entry.
+func (e Entry) clone() Entry {
+	e.Senses = slices.Clone(e.Senses)
+	for i, s := range e.Senses {
+		e.Senses[i] = s.clone()
+	}
+	return e
+}
+
+func (s Sense) clone() Sense {
+	s.Examples = slices.Clone(s.Examples)
+	s.Synonyms = slices.Clone(s.Synonyms)
+	return s
+}
+
+// Glossary is a dictionary whose words can have several senses. Add,
+// Update and Search work on the first sense, so a Glossary can stand in
+// for a Dictionary. Like Dictionary it is not safe for concurrent use.
+//
+// Like SafeDictionary it caches an Index for prefix and fuzzy queries,
+// dropped whenever a word is added or removed.
+type Glossary struct {
+	entries map[string]*Entry
+	idx     *Index
+
+	// Now stamps modified entries. It defaults to time.Now.
+	Now func() time.Time
+}
+
+func NewGlossary() *Glossary {
+	return &Glossary{entries: map[string]*Entry{}, Now: time.Now}
+}
+
+func (g *Glossary) now() time.Time {
+	if g.Now == nil {
+		return time.Now()
+	}
+	return g.Now()
+}
+
+// Entry returns a copy of the entry for word.
+func (g *Glossary) Entry(word string) (Entry, error) {
+	entry, ok := g.entries[word]
+	if !ok {
+		return Entry{}, notFound(g.index(), word)
+	}
+	return entry.clone(), nil
+}
+
+// AddEntry adds a word with all of its senses.
+func (g *Glossary) AddEntry(entry Entry) error {
+	if _, ok := g.entries[entry.Word]; ok {
+		return &WordError{Op: OpAdd, Word: entry.Word, Err: ErrWordExists}
+	}
+	if len(entry.Senses) == 0 {
+		return &WordError{Op: OpAdd, Word: entry.Word, Err: ErrNoSenses}
+	}
+	entry = entry.clone()
+	entry.Modified = g.now()
+	g.entries[entry.Word] = &entry
+	g.idx = nil
+	return nil
+}
+
+// AddSense appends a sense to word, adding the word if it is new.
+func (g *Glossary) AddSense(word string, sense Sense) {
+	entry, ok := g.entries[word]
+	if !ok {
+		entry = &Entry{Word: word}
+		g.entries[word] = entry
+		g.idx = nil
+	}
+	entry.Senses = append(entry.Senses, sense.clone())
+	entry.Modified = g.now()
+}
+
+// RemoveSense removes the sense at index i of word. Removing the last
+// sense removes the word.
+func (g *Glossary) RemoveSense(word string, i int) error {
+	entry, ok := g.entries[word]
+	if !ok {
+		return &WordError{Op: OpRemoveSense, Word: word, Err: ErrWordDoesNotExists}
+	}
+	if i < 0 || i >= len(entry.Senses) {
+		return &WordError{Op: OpRemoveSense, Word: word, Err: ErrSenseNotFound}
+	}
+
+	entry.Senses = slices.Delete(entry.Senses, i, i+1)
+	if len(entry.Senses) == 0 {
+		delete(g.entries, word)
+		g.idx = nil
+		return nil
+	}
+	entry.Modified = g.now()
+	return nil
+}
+
+// Search returns the definition of the first sense of word.
+func (g *Glossary) Search(word string) (string, error) {
+	entry, ok := g.entries[word]
+	if !ok {
+		return "", notFound(g.index(), word)
+	}
+	return entry.Senses[0].Definition, nil
+}
+
+// Add adds word with a single sense.
+func (g *Glossary) Add(word, definition string) error {
+	return g.AddEntry(Entry{Word: word, Senses: []Sense{{Definition: definition}}})
+}
+
+// Update replaces the definition of the first sense of word, keeping the
+// rest of that sense and any other senses.
+func (g *Glossary) Update(word, definition string) error {
+	entry, ok := g.entries[word]
+	if !ok {
+		return &WordError{Op: OpUpdate, Word: word, Err: ErrWordDoesNotExists}
+	}
+	entry.Senses[0].Definition = definition
+	entry.Modified = g.now()
+	return nil
+}
+
+func (g *Glossary) Delete(word string) {
+	delete(g.entries, word)
+	g.idx = nil
+}
+
+func (g *Glossary) SearchPrefix(prefix string) []string {
+	return g.index().SearchPrefix(prefix)
+}
+
+func (g *Glossary) Suggest(word string, maxDistance int) []string {
+	return g.index().Suggest(word, maxDistance)
+}
+
+func (g *Glossary) index() *Index {
+	if g.idx == nil {
+		words := make([]string, 0, len(g.entries))
+		for word := range g.entries {
+			words = append(words, word)
+		}
+		g.idx = newIndex(words)
+	}
+	return g.idx
+}
@@ -0,0 +1,132 @@
+package maps
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGlossaryBehaviour(t *testing.T) {
+	testWordStore(t, func() WordStore { return NewGlossary() }, false)
+}
+
+func TestGlossarySenses(t *testing.T) {
+	run := Sense{
+		Definition:   "move at a speed faster than a walk",
+		PartOfSpeech: "verb",
+		Examples:     []string{"she ran to the shop"},
+		Synonyms:     []string{"sprint", "jog"},
+	}
+	trip := Sense{Definition: "a journey or route", PartOfSpeech: "noun", Examples: []string{"a run to the coast"}}
+
+	t.Run("an entry keeps every sense and its metadata", func(t *testing.T) {
+		glossary, clock := newTestGlossary()
+
+		err := glossary.AddEntry(Entry{Word: "run", Senses: []Sense{run, trip}})
+
+		assertError(t, err, nil)
+		assertEntry(t, glossary, Entry{Word: "run", Senses: []Sense{run, trip}, Modified: clock.now})
+	})
+
+	t.Run("an entry needs a sense", func(t *testing.T) {
+		glossary, _ := newTestGlossary()
+
+		assertError(t, glossary.AddEntry(Entry{Word: "run"}), ErrNoSenses)
+	})
+
+	t.Run("adding a sense appends it and bumps the timestamp", func(t *testing.T) {
+		glossary, clock := newTestGlossary()
+		glossary.AddSense("run", run)
+		clock.advance(time.Hour)
+
+		glossary.AddSense("run", trip)
+
+		assertEntry(t, glossary, Entry{Word: "run", Senses: []Sense{run, trip}, Modified: clock.now})
+	})
+
+	t.Run("removing a sense keeps the others", func(t *testing.T) {
+		glossary, clock := newTestGlossary()
+		glossary.AddEntry(Entry{Word: "run", Senses: []Sense{run, trip}})
+		clock.advance(time.Hour)
+
+		assertError(t, glossary.RemoveSense("run", 0), nil)
+
+		assertEntry(t, glossary, Entry{Word: "run", Senses: []Sense{trip}, Modified: clock.now})
+		got, _ := glossary.Search("run")
+		assertStrings(t, got, trip.Definition)
+	})
+
+	t.Run("removing the last sense removes the word", func(t *testing.T) {
+		glossary, _ := newTestGlossary()
+		glossary.AddSense("run", run)
+
+		glossary.RemoveSense("run", 0)
+
+		_, err := glossary.Entry("run")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("removing a sense that does not exist", func(t *testing.T) {
+		glossary, _ := newTestGlossary()
+		glossary.AddSense("run", run)
+
+		assertError(t, glossary.RemoveSense("run", 1), ErrSenseNotFound)
+		assertError(t, glossary.RemoveSense("walk", 0), ErrWordDoesNotExists)
+	})
+
+	t.Run("update only changes the first definition", func(t *testing.T) {
+		glossary, _ := newTestGlossary()
+		glossary.AddEntry(Entry{Word: "run", Senses: []Sense{run, trip}})
+
+		glossary.Update("run", "go quickly")
+
+		entry, _ := glossary.Entry("run")
+		want := run
+		want.Definition = "go quickly"
+		if !reflect.DeepEqual(entry.Senses, []Sense{want, trip}) {
+			t.Errorf("got senses %v want %v", entry.Senses, []Sense{want, trip})
+		}
+	})
+
+	t.Run("returned entries are copies", func(t *testing.T) {
+		glossary, _ := newTestGlossary()
+		glossary.AddSense("run", run)
+
+		entry, _ := glossary.Entry("run")
+		entry.Senses[0].Synonyms[0] = "crawl"
+
+		again, _ := glossary.Entry("run")
+		assertStrings(t, again.Senses[0].Synonyms[0], "sprint")
+	})
+}
+
+type stubClock struct {
+	now time.Time
+}
+
+func (c *stubClock) Now() time.Time {
+	return c.now
+}
+
+func (c *stubClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestGlossary() (*Glossary, *stubClock) {
+	clock := &stubClock{time.Date(2024, time.September, 5, 12, 0, 0, 0, time.UTC)}
+	glossary := NewGlossary()
+	glossary.Now = clock.Now
+	return glossary, clock
+}
+
+func assertEntry(t testing.TB, glossary *Glossary, want Entry) {
+	t.Helper()
+
+	got, err := glossary.Entry(want.Word)
+	if err != nil {
+		t.Fatal("should find entry:", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got entry %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,249 @@
+package maps
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// Change is one edit to a HistoryDictionary. Old is empty for OpAdd and
+// New is empty for OpDelete.
+type Change struct {
+	Op   OpKind
+	Word string
+	Old  string
+	New  string
+}
+
+// inverse is the change that undoes c.
+func (c Change) inverse() Change {
+	switch c.Op {
+	case OpAdd:
+		return Change{OpDelete, c.Word, c.New, ""}
+	case OpDelete:
+		return Change{OpAdd, c.Word, "", c.Old}
+	default:
+		return Change{OpUpdate, c.Word, c.New, c.Old}
+	}
+}
+
+// HistoryDictionary is a dictionary that records every Add, Update and
+// Delete, tells subscribers about them and can undo and redo them. At most
+// limit changes are kept for Undo; older ones are forgotten. It is safe
+// for concurrent use.
+//
+// Like SafeDictionary it caches an Index for prefix and fuzzy queries and
+// for the suggestions of a missed search, dropped when a word is added or
+// removed.
+type HistoryDictionary struct {
+	mu    sync.Mutex
+	words Dictionary
+	index *Index
+	limit int
+	undo  []Change
+	redo  []Change
+
+	subscribers map[int]func(Change)
+	nextID      int
+}
+
+// NewHistoryDictionary starts a history over words, which it takes
+// ownership of. A limit below 1 keeps no history at all.
+func NewHistoryDictionary(words Dictionary, limit int) *HistoryDictionary {
+	if words == nil {
+		words = Dictionary{}
+	}
+	return &HistoryDictionary{
+		words:       words,
+		limit:       max(limit, 0),
+		subscribers: map[int]func(Change){},
+	}
+}
+
+// Subscribe calls fn with every change, including those made by Undo and
+// Redo, in the order they happen. fn runs while the dictionary is locked,
+// so it must not call back into it. The returned func unsubscribes.
+func (h *HistoryDictionary) Subscribe(fn func(Change)) (unsubscribe func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextID
+	h.nextID++
+	h.subscribers[id] = fn
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers, id)
+	}
+}
+
+// Watch delivers changes on a channel with room for buffer changes. A
+// change that would not fit is dropped rather than blocking writers. The
+// channel is closed by the returned func.
+func (h *HistoryDictionary) Watch(buffer int) (<-chan Change, func()) {
+	changes := make(chan Change, buffer)
+	unsubscribe := h.Subscribe(func(c Change) {
+		select {
+		case changes <- c:
+		default:
+		}
+	})
+
+	var once sync.Once
+	return changes, func() {
+		once.Do(func() {
+			unsubscribe()
+			close(changes)
+		})
+	}
+}
+
+func (h *HistoryDictionary) Search(word string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	definition, err := h.words.search(word)
+	if err != nil {
+		return "", notFound(h.cachedIndex(), word)
+	}
+	return definition, nil
+}
+
+func (h *HistoryDictionary) SearchPrefix(prefix string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.cachedIndex().SearchPrefix(prefix)
+}
+
+func (h *HistoryDictionary) Suggest(word string, maxDistance int) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.cachedIndex().Suggest(word, maxDistance)
+}
+
+// cachedIndex must be called with h.mu held.
+func (h *HistoryDictionary) cachedIndex() *Index {
+	if h.index == nil {
+		h.index = NewIndex(h.words)
+	}
+	return h.index
+}
+
+func (h *HistoryDictionary) Add(word, definition string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.words.Add(word, definition); err != nil {
+		return err
+	}
+	h.index = nil
+	h.record(Change{OpAdd, word, "", definition})
+	return nil
+}
+
+func (h *HistoryDictionary) Update(word, definition string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old := h.words[word]
+	if err := h.words.Update(word, definition); err != nil {
+		return err
+	}
+	h.record(Change{OpUpdate, word, old, definition})
+	return nil
+}
+
+// Delete records nothing when word was not in the dictionary.
+func (h *HistoryDictionary) Delete(word string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old, ok := h.words[word]
+	if !ok {
+		return
+	}
+	h.words.Delete(word)
+	h.index = nil
+	h.record(Change{OpDelete, word, old, ""})
+}
+
+// Undo reverts the most recent change and returns the change it made.
+func (h *HistoryDictionary) Undo() (Change, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.undo) == 0 {
+		return Change{}, ErrNothingToUndo
+	}
+	last := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, last)
+
+	return h.apply(last.inverse()), nil
+}
+
+// Redo reapplies the most recently undone change.
+func (h *HistoryDictionary) Redo() (Change, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.redo) == 0 {
+		return Change{}, ErrNothingToRedo
+	}
+	next := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.push(next)
+
+	return h.apply(next), nil
+}
+
+// History returns the changes Undo can revert, oldest first.
+func (h *HistoryDictionary) History() []Change {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Change(nil), h.undo...)
+}
+
+// record must be called with h.mu held. A new edit makes the redo stack
+// meaningless, so it is cleared.
+func (h *HistoryDictionary) record(c Change) {
+	h.redo = nil
+	h.push(c)
+	h.notify(c)
+}
+
+func (h *HistoryDictionary) push(c Change) {
+	if h.limit == 0 {
+		return
+	}
+	if len(h.undo) == h.limit {
+		h.undo = append(h.undo[:0], h.undo[1:]...)
+	}
+	h.undo = append(h.undo, c)
+}
+
+// apply makes c without touching the history. Undo and Redo only apply
+// changes that are known to be valid, so the map is written directly.
+func (h *HistoryDictionary) apply(c Change) Change {
+	if c.Op == OpDelete {
+		delete(h.words, c.Word)
+	} else {
+		h.words[c.Word] = c.New
+	}
+	if c.Op != OpUpdate {
+		h.index = nil
+	}
+	h.notify(c)
+	return c
+}
+
+func (h *HistoryDictionary) notify(c Change) {
+	for _, fn := range h.subscribers {
+		fn(c)
+	}
+}
@@ -0,0 +1,164 @@
+package maps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistoryDictionaryBehaviour(t *testing.T) {
+	testWordStore(t, func() WordStore { return NewHistoryDictionary(nil, 10) }, true)
+}
+
+func TestHistoryDictionaryChanges(t *testing.T) {
+	dictionary := NewHistoryDictionary(Dictionary{"test": "this is just a test"}, 10)
+
+	var got []Change
+	dictionary.Subscribe(func(c Change) { got = append(got, c) })
+
+	dictionary.Add("new", "a new word")
+	dictionary.Update("test", "new definition")
+	dictionary.Delete("new")
+	dictionary.Delete("missing")
+	dictionary.Add("test", "already there")
+
+	want := []Change{
+		{OpAdd, "new", "", "a new word"},
+		{OpUpdate, "test", "this is just a test", "new definition"},
+		{OpDelete, "new", "a new word", ""},
+	}
+	assertChanges(t, got, want)
+	assertChanges(t, dictionary.History(), want)
+}
+
+func TestHistoryDictionaryUndoRedo(t *testing.T) {
+	t.Run("undo reverts changes newest first", func(t *testing.T) {
+		dictionary := NewHistoryDictionary(Dictionary{"test": "this is just a test"}, 10)
+		dictionary.Add("new", "a new word")
+		dictionary.Update("test", "new definition")
+		dictionary.Delete("new")
+
+		for range 3 {
+			if _, err := dictionary.Undo(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		assertDictionary(t, dictionary.words, Dictionary{"test": "this is just a test"})
+		_, err := dictionary.Undo()
+		assertError(t, err, ErrNothingToUndo)
+	})
+
+	t.Run("undo and redo keep prefix search current", func(t *testing.T) {
+		dictionary := NewHistoryDictionary(nil, 10)
+		dictionary.Add("test", "one")
+		dictionary.Add("tester", "two")
+		assertWords(t, dictionary.SearchPrefix("test"), []string{"test", "tester"})
+
+		dictionary.Undo()
+		assertWords(t, dictionary.SearchPrefix("test"), []string{"test"})
+
+		dictionary.Redo()
+		assertWords(t, dictionary.SearchPrefix("test"), []string{"test", "tester"})
+	})
+
+	t.Run("redo reapplies undone changes", func(t *testing.T) {
+		dictionary := NewHistoryDictionary(nil, 10)
+		dictionary.Add("test", "one")
+		dictionary.Update("test", "two")
+
+		dictionary.Undo()
+		dictionary.Undo()
+		dictionary.Redo()
+		change, err := dictionary.Redo()
+
+		assertError(t, err, nil)
+		assertChanges(t, []Change{change}, []Change{{OpUpdate, "test", "one", "two"}})
+		assertDictionary(t, dictionary.words, Dictionary{"test": "two"})
+
+		_, err = dictionary.Redo()
+		assertError(t, err, ErrNothingToRedo)
+	})
+
+	t.Run("a new edit clears redo", func(t *testing.T) {
+		dictionary := NewHistoryDictionary(nil, 10)
+		dictionary.Add("test", "one")
+		dictionary.Undo()
+
+		dictionary.Add("other", "two")
+
+		_, err := dictionary.Redo()
+		assertError(t, err, ErrNothingToRedo)
+	})
+
+	t.Run("subscribers see undo and redo", func(t *testing.T) {
+		dictionary := NewHistoryDictionary(nil, 10)
+		dictionary.Add("test", "one")
+
+		var got []Change
+		dictionary.Subscribe(func(c Change) { got = append(got, c) })
+		dictionary.Undo()
+		dictionary.Redo()
+
+		assertChanges(t, got, []Change{
+			{OpDelete, "test", "one", ""},
+			{OpAdd, "test", "", "one"},
+		})
+	})
+
+	t.Run("history is bounded", func(t *testing.T) {
+		dictionary := NewHistoryDictionary(nil, 2)
+		dictionary.Add("a", "1")
+		dictionary.Add("b", "2")
+		dictionary.Add("c", "3")
+
+		assertChanges(t, dictionary.History(), []Change{
+			{OpAdd, "b", "", "2"},
+			{OpAdd, "c", "", "3"},
+		})
+
+		dictionary.Undo()
+		dictionary.Undo()
+		_, err := dictionary.Undo()
+
+		assertError(t, err, ErrNothingToUndo)
+		assertDictionary(t, dictionary.words, Dictionary{"a": "1"})
+	})
+}
+
+func TestHistoryDictionaryWatch(t *testing.T) {
+	dictionary := NewHistoryDictionary(nil, 10)
+	changes, stop := dictionary.Watch(1)
+
+	dictionary.Add("test", "one")
+	dictionary.Add("dropped", "the buffer is full")
+
+	assertChanges(t, []Change{<-changes}, []Change{{OpAdd, "test", "", "one"}})
+
+	stop()
+	stop()
+	dictionary.Add("after", "stop")
+	if _, open := <-changes; open {
+		t.Error("expected the channel to be closed")
+	}
+}
+
+func TestHistoryDictionaryUnsubscribe(t *testing.T) {
+	dictionary := NewHistoryDictionary(nil, 10)
+	calls := 0
+	unsubscribe := dictionary.Subscribe(func(Change) { calls++ })
+
+	dictionary.Add("test", "one")
+	unsubscribe()
+	dictionary.Add("other", "two")
+
+	if calls != 1 {
+		t.Errorf("got %d calls want %d", calls, 1)
+	}
+}
+
+func assertChanges(t testing.TB, got, want []Change) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got changes %+v want %+v", got, want)
+	}
+}
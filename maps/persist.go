@@ -0,0 +1,71 @@
+package maps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LoadError reports a problem with one word of a file being loaded. It
+// unwraps to the *WordError behind it, so errors.Is(err, ErrWordExists) works.
+type LoadError struct {
+	Path string
+	Line int
+	Word string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Save writes the dictionary to path using codec. The data goes to a
+// temporary file in the same directory which is synced and then renamed
+// over path, so readers see either the old file or the new one.
+func (d Dictionary) Save(path string, codec Codec) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if err := codec.Encode(tmp, d.records()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads a dictionary saved with codec. A word appearing twice is an
+// error, reported as a *LoadError for the second occurrence.
+func Load(path string, codec Codec) (Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := codec.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	d := Dictionary{}
+	for _, rec := range records {
+		if err := d.Add(rec.Word, rec.Definition); err != nil {
+			return nil, &LoadError{path, rec.Line, rec.Word, err}
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,145 @@
+package maps
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var codecs = []struct {
+	name  string
+	codec Codec
+}{
+	{"json", JSONCodec{}},
+	{"csv", CSVCodec{}},
+	{"gob", GobCodec{}},
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dictionary := Dictionary{
+		"test":    "this is just a test",
+		"quoted":  `a "quoted", comma separated definition`,
+		"newline": "first line\nsecond line",
+		"unicode": "ñandú",
+	}
+
+	for _, c := range codecs {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "glossary."+c.name)
+
+			if err := dictionary.Save(path, c.codec); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path, c.codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertDictionary(t, got, dictionary)
+		})
+	}
+}
+
+func TestSaveIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "glossary.json")
+
+	Dictionary{"old": "version"}.Save(path, JSONCodec{})
+	if err := (Dictionary{"new": "version"}).Save(path, JSONCodec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := Load(path, JSONCodec{})
+	assertDictionary(t, got, Dictionary{"new": "version"})
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the saved file to remain, found %d entries", len(entries))
+	}
+}
+
+func TestLoadRejectsDuplicates(t *testing.T) {
+	cases := []struct {
+		name     string
+		codec    Codec
+		contents string
+		line     int
+	}{
+		{
+			name:     "json",
+			codec:    JSONCodec{},
+			contents: "{\n  \"test\": \"one\",\n  \"other\": \"two\",\n  \"test\": \"three\"\n}\n",
+			line:     4,
+		},
+		{
+			name:     "csv",
+			codec:    CSVCodec{},
+			contents: "word,definition\ntest,one\nother,two\ntest,three\n",
+			line:     4,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "glossary")
+			os.WriteFile(path, []byte(tt.contents), 0o644)
+
+			_, err := Load(path, tt.codec)
+
+			assertLoadError(t, err, tt.line, "test")
+		})
+	}
+
+	t.Run("gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		GobCodec{}.Encode(&buf, []Record{{Word: "test"}, {Word: "test"}})
+		path := filepath.Join(t.TempDir(), "glossary.gob")
+		os.WriteFile(path, buf.Bytes(), 0o644)
+
+		_, err := Load(path, GobCodec{})
+
+		assertLoadError(t, err, 2, "test")
+	})
+}
+
+func TestLoadRejectsMalformedFiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		codec    Codec
+		contents string
+	}{
+		{"json array", JSONCodec{}, `["test"]`},
+		{"json definition is not a string", JSONCodec{}, `{"test": 1}`},
+		{"json truncated", JSONCodec{}, `{"test": "one"`},
+		{"csv wrong column count", CSVCodec{}, "word,definition\ntest\n"},
+		{"gob garbage", GobCodec{}, "not gob"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "glossary")
+			os.WriteFile(path, []byte(tt.contents), 0o644)
+
+			if _, err := Load(path, tt.codec); err == nil {
+				t.Error("expected an error loading a malformed file")
+			}
+		})
+	}
+}
+
+func assertLoadError(t testing.TB, err error, line int, word string) {
+	t.Helper()
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("got %v want a *LoadError", err)
+	}
+	if !errors.Is(err, ErrWordExists) {
+		t.Errorf("expected %v to match %v", err, ErrWordExists)
+	}
+	if loadErr.Line != line || loadErr.Word != word {
+		t.Errorf("got %q on line %d want %q on line %d", loadErr.Word, loadErr.Line, word, line)
+	}
+}
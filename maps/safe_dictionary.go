@@ -0,0 +1,91 @@
+package maps
+
+import "sync"
+
+// WordStore is the behaviour shared by Dictionary and SafeDictionary.
+type WordStore interface {
+	Search(word string) (string, error)
+	Add(word, definition string) error
+	Update(word, definition string) error
+	Delete(word string)
+	SearchPrefix(prefix string) []string
+	Suggest(word string, maxDistance int) []string
+}
+
+// SafeDictionary is a Dictionary that can be shared between goroutines.
+// Readers share a read lock; Add, Update, Delete and Batch take the write lock.
+//
+// It caches an Index for prefix and fuzzy queries, dropped on every write
+// that changes the set of words and rebuilt on the next query.
+type SafeDictionary struct {
+	mu    sync.RWMutex
+	words Dictionary
+
+	indexMu sync.Mutex
+	index   *Index
+}
+
+func NewSafeDictionary() *SafeDictionary {
+	return &SafeDictionary{words: Dictionary{}}
+}
+
+func (s *SafeDictionary) Search(word string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	definition, err := s.words.search(word)
+	if err != nil {
+		return "", notFound(s.cachedIndex(), word)
+	}
+	return definition, nil
+}
+
+func (s *SafeDictionary) SearchPrefix(prefix string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cachedIndex().SearchPrefix(prefix)
+}
+
+func (s *SafeDictionary) Suggest(word string, maxDistance int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cachedIndex().Suggest(word, maxDistance)
+}
+
+// cachedIndex must be called with s.mu held. Several readers may hold the
+// read lock at once, hence the separate lock around the cache itself.
+func (s *SafeDictionary) cachedIndex() *Index {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.index == nil {
+		s.index = NewIndex(s.words)
+	}
+	return s.index
+}
+
+func (s *SafeDictionary) Add(word, definition string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = nil
+	return s.words.Add(word, definition)
+}
+
+func (s *SafeDictionary) Update(word, definition string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.words.Update(word, definition)
+}
+
+func (s *SafeDictionary) Delete(word string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = nil
+	s.words.Delete(word)
+}
+
+func (s *SafeDictionary) Batch(ops ...Op) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = nil
+	return s.words.Batch(ops...)
+}
@@ -0,0 +1,108 @@
+package maps
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// how far a word may be from a missed search to be suggested
+	suggestionDistance = 2
+	maxSuggestions     = 3
+)
+
+// Index is a sorted list of words answering prefix and fuzzy queries.
+// A plain Dictionary builds one for each query, since a map has nowhere to
+// keep it; SafeDictionary, HistoryDictionary and Glossary keep theirs
+// until a word is added or removed. Build one with NewIndex when querying
+// the same Dictionary repeatedly.
+type Index struct {
+	words []string
+}
+
+func NewIndex(d Dictionary) *Index {
+	words := make([]string, 0, len(d))
+	for word := range d {
+		words = append(words, word)
+	}
+	return newIndex(words)
+}
+
+func newIndex(words []string) *Index {
+	sort.Strings(words)
+	return &Index{words}
+}
+
+// SearchPrefix returns the words starting with prefix, in order.
+func (idx *Index) SearchPrefix(prefix string) []string {
+	start := sort.SearchStrings(idx.words, prefix)
+	end := start
+	for end < len(idx.words) && strings.HasPrefix(idx.words[end], prefix) {
+		end++
+	}
+	return append([]string(nil), idx.words[start:end]...)
+}
+
+// Suggest returns the words within maxDistance edits of word, closest first.
+func (idx *Index) Suggest(word string, maxDistance int) []string {
+	type candidate struct {
+		word     string
+		distance int
+	}
+	var candidates []candidate
+	for _, w := range idx.words {
+		if d := levenshtein(word, w); d <= maxDistance {
+			candidates = append(candidates, candidate{w, d})
+		}
+	}
+	// words are already sorted, so a stable sort keeps ties alphabetical
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.word
+	}
+	return suggestions
+}
+
+func (d Dictionary) SearchPrefix(prefix string) []string {
+	return NewIndex(d).SearchPrefix(prefix)
+}
+
+func (d Dictionary) Suggest(word string, maxDistance int) []string {
+	return NewIndex(d).Suggest(word, maxDistance)
+}
+
+// notFound builds the error for a missed search, with suggestions when
+// there are any close words.
+func notFound(idx *Index, word string) error {
+	suggestions := idx.Suggest(word, suggestionDistance)
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return &WordError{Op: OpSearch, Word: word, Err: ErrNotFound, Suggestions: suggestions}
+}
+
+// levenshtein counts the single rune insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,115 @@
+package maps
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSearchPrefix(t *testing.T) {
+	dictionary := Dictionary{"car": "", "card": "", "care": "", "cat": "", "dog": ""}
+
+	cases := []struct {
+		prefix string
+		want   []string
+	}{
+		{"car", []string{"car", "card", "care"}},
+		{"ca", []string{"car", "card", "care", "cat"}},
+		{"", []string{"car", "card", "care", "cat", "dog"}},
+		{"cow", nil},
+		{"z", nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.prefix, func(t *testing.T) {
+			assertWords(t, dictionary.SearchPrefix(tt.prefix), tt.want)
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	dictionary := Dictionary{"test": "", "text": "", "best": "", "toast": "", "unrelated": ""}
+
+	t.Run("closest words first, ties alphabetical", func(t *testing.T) {
+		assertWords(t, dictionary.Suggest("tesst", 2), []string{"test", "best", "text", "toast"})
+	})
+
+	t.Run("respects the maximum distance", func(t *testing.T) {
+		assertWords(t, dictionary.Suggest("tesst", 1), []string{"test"})
+	})
+
+	t.Run("exact word has distance zero", func(t *testing.T) {
+		assertWords(t, dictionary.Suggest("test", 0), []string{"test"})
+	})
+}
+
+func TestSearchSuggestions(t *testing.T) {
+	t.Run("did you mean", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+
+		_, err := dictionary.Search("tset")
+
+		want := `search "tset": could not find the word you are looking for (did you mean "test"?)`
+		if err == nil || err.Error() != want {
+			t.Errorf("got error %v want %q", err, want)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected %v to match %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("at most three suggestions", func(t *testing.T) {
+		dictionary := Dictionary{"aa": "", "ab": "", "ac": "", "ad": ""}
+
+		_, err := dictionary.Search("a")
+
+		var notFound *WordError
+		if !errors.As(err, &notFound) {
+			t.Fatalf("got %v want a *WordError", err)
+		}
+		assertWords(t, notFound.Suggestions, []string{"aa", "ab", "ac"})
+	})
+
+	t.Run("no suggestions when nothing is close", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+
+		_, err := dictionary.Search("unrelated")
+
+		assertError(t, err, ErrNotFound)
+		want := `search "unrelated": could not find the word you are looking for`
+		if err.Error() != want {
+			t.Errorf("got error %q want %q", err, want)
+		}
+	})
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"test", "", 4},
+		{"", "test", 4},
+		{"test", "test", 0},
+		{"test", "tset", 2},
+		{"kitten", "sitting", 3},
+		{"ñandú", "nandu", 2},
+	}
+
+	for _, tt := range cases {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func assertWords(t testing.TB, got, want []string) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got words %v want %v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package maps
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// WordResponse is the JSON body returned for a word.
+type WordResponse struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+}
+
+// WordRequest is the JSON body accepted by POST and PUT.
+type WordRequest struct {
+	Definition string `json:"definition"`
+}
+
+// ErrorResponse is the JSON body of every failed request.
+type ErrorResponse struct {
+	Error       string   `json:"error"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// DictionaryServer serves a WordStore over HTTP the way PlayerServer in
+// app serves scores. Requests are handled concurrently, so give it a
+// SafeDictionary rather than a bare Dictionary.
+type DictionaryServer struct {
+	store WordStore
+	http.Handler
+}
+
+func NewDictionaryServer(store WordStore) *DictionaryServer {
+	s := new(DictionaryServer)
+	s.store = store
+
+	router := http.NewServeMux()
+	router.Handle("GET /words/{word}", http.HandlerFunc(s.searchHandler))
+	router.Handle("POST /words/{word}", http.HandlerFunc(s.addHandler))
+	router.Handle("PUT /words/{word}", http.HandlerFunc(s.updateHandler))
+	router.Handle("DELETE /words/{word}", http.HandlerFunc(s.deleteHandler))
+
+	s.Handler = router
+	return s
+}
+
+func (s *DictionaryServer) searchHandler(w http.ResponseWriter, r *http.Request) {
+	word := r.PathValue("word")
+
+	definition, err := s.store.Search(word)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, WordResponse{word, definition})
+}
+
+func (s *DictionaryServer) addHandler(w http.ResponseWriter, r *http.Request) {
+	word := r.PathValue("word")
+
+	req, ok := readWordRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := s.store.Add(word, req.Definition); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, WordResponse{word, req.Definition})
+}
+
+func (s *DictionaryServer) updateHandler(w http.ResponseWriter, r *http.Request) {
+	word := r.PathValue("word")
+
+	req, ok := readWordRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := s.store.Update(word, req.Definition); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, WordResponse{word, req.Definition})
+}
+
+func (s *DictionaryServer) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	s.store.Delete(r.PathValue("word"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func readWordRequest(w http.ResponseWriter, r *http.Request) (WordRequest, bool) {
+	var req WordRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body: " + err.Error()})
+		return WordRequest{}, false
+	}
+	return req, true
+}
+
+// writeError maps the dictionary's errors onto status codes.
+func writeError(w http.ResponseWriter, err error) {
+	res := ErrorResponse{Error: err.Error()}
+	var wordErr *WordError
+	if errors.As(err, &wordErr) {
+		res.Suggestions = wordErr.Suggestions
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrWordDoesNotExists):
+		writeJSON(w, http.StatusNotFound, res)
+	case errors.Is(err, ErrWordExists):
+		writeJSON(w, http.StatusConflict, res)
+	default:
+		writeJSON(w, http.StatusInternalServerError, res)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,166 @@
+package maps
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGETWord(t *testing.T) {
+	server := NewDictionaryServer(Dictionary{"test": "this is just a test"})
+
+	t.Run("returns the definition", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newWordRequest(http.MethodGet, "test", ""))
+
+		assertStatus(t, res, http.StatusOK)
+		assertContentType(t, res, "application/json")
+		assertJSON(t, res.Body, WordResponse{"test", "this is just a test"})
+	})
+
+	t.Run("returns 404 with suggestions for a missing word", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newWordRequest(http.MethodGet, "tset", ""))
+
+		assertStatus(t, res, http.StatusNotFound)
+		assertJSON(t, res.Body, ErrorResponse{
+			Error:       `search "tset": could not find the word you are looking for (did you mean "test"?)`,
+			Suggestions: []string{"test"},
+		})
+	})
+}
+
+func TestPOSTWord(t *testing.T) {
+	t.Run("adds a new word", func(t *testing.T) {
+		dictionary := Dictionary{}
+		server := NewDictionaryServer(dictionary)
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newWordRequest(http.MethodPost, "test", `{"definition": "this is just a test"}`))
+
+		assertStatus(t, res, http.StatusCreated)
+		assertJSON(t, res.Body, WordResponse{"test", "this is just a test"})
+		assertDefinition(t, dictionary, "test", "this is just a test")
+	})
+
+	t.Run("returns 409 for an existing word", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newWordRequest(http.MethodPost, "test", `{"definition": "new test"}`))
+
+		assertStatus(t, res, http.StatusConflict)
+		assertJSON(t, res.Body, ErrorResponse{Error: `add "test": ` + ErrWordExists.Error()})
+		assertDefinition(t, dictionary, "test", "this is just a test")
+	})
+
+	t.Run("returns 400 for a malformed body", func(t *testing.T) {
+		server := NewDictionaryServer(Dictionary{})
+
+		for _, body := range []string{"", "not json", `{"meaning": "wrong field"}`} {
+			res := httptest.NewRecorder()
+			server.ServeHTTP(res, newWordRequest(http.MethodPost, "test", body))
+
+			assertStatus(t, res, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestPUTWord(t *testing.T) {
+	t.Run("updates an existing word", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newWordRequest(http.MethodPut, "test", `{"definition": "new definition"}`))
+
+		assertStatus(t, res, http.StatusOK)
+		assertDefinition(t, dictionary, "test", "new definition")
+	})
+
+	t.Run("returns 404 for a missing word", func(t *testing.T) {
+		server := NewDictionaryServer(Dictionary{})
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newWordRequest(http.MethodPut, "test", `{"definition": "new definition"}`))
+
+		assertStatus(t, res, http.StatusNotFound)
+		assertJSON(t, res.Body, ErrorResponse{Error: `update "test": ` + ErrWordDoesNotExists.Error()})
+	})
+}
+
+func TestDELETEWord(t *testing.T) {
+	dictionary := Dictionary{"test": "this is just a test"}
+	server := NewDictionaryServer(dictionary)
+
+	res := httptest.NewRecorder()
+	server.ServeHTTP(res, newWordRequest(http.MethodDelete, "test", ""))
+
+	assertStatus(t, res, http.StatusNoContent)
+	_, err := dictionary.Search("test")
+	assertError(t, err, ErrNotFound)
+}
+
+func TestDictionaryServerConcurrently(t *testing.T) {
+	server := httptest.NewServer(NewDictionaryServer(NewSafeDictionary()))
+	defer server.Close()
+
+	done := make(chan struct{})
+	for _, word := range []string{"a", "b", "c", "d"} {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/words/"+word, strings.NewReader(`{"definition": "x"}`))
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			res.Body.Close()
+			if res.StatusCode != http.StatusCreated {
+				t.Errorf("got status %d adding %q", res.StatusCode, word)
+			}
+		}()
+	}
+	for range 4 {
+		<-done
+	}
+}
+
+// ---------------------------------Helper Functions---------------------------------
+
+func newWordRequest(method, word, body string) *http.Request {
+	req, _ := http.NewRequest(method, "/words/"+word, strings.NewReader(body))
+	return req
+}
+
+func assertStatus(t testing.TB, res *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if res.Code != want {
+		t.Errorf("did not get correct status, got %d want %d", res.Code, want)
+	}
+}
+
+func assertContentType(t testing.TB, res *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if got := res.Header().Get("Content-Type"); got != want {
+		t.Errorf("response did not have content-type of %s, got %s", want, got)
+	}
+}
+
+// assertJSON decodes body into a value of want's type and compares them.
+func assertJSON[T any](t testing.TB, body io.Reader, want T) {
+	t.Helper()
+
+	var got T
+	if err := json.NewDecoder(body).Decode(&got); err != nil {
+		t.Fatalf("unable to parse response body: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
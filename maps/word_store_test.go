@@ -0,0 +1,139 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Both implementations must behave the same; only SafeDictionary has to
+// survive the concurrent part of the suite.
+func TestDictionaryBehaviour(t *testing.T) {
+	testWordStore(t, func() WordStore { return Dictionary{} }, false)
+}
+
+func TestSafeDictionaryBehaviour(t *testing.T) {
+	testWordStore(t, func() WordStore { return NewSafeDictionary() }, true)
+}
+
+func testWordStore(t *testing.T, newStore func() WordStore, concurrent bool) {
+	t.Run("search finds an added word", func(t *testing.T) {
+		store := newStore()
+
+		assertError(t, store.Add("test", "this is just a test"), nil)
+		assertSearch(t, store, "test", "this is just a test")
+	})
+
+	t.Run("search reports unknown words", func(t *testing.T) {
+		store := newStore()
+
+		_, err := store.Search("unknown")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("add refuses an existing word", func(t *testing.T) {
+		store := newStore()
+		store.Add("test", "this is just a test")
+
+		assertError(t, store.Add("test", "new test"), ErrWordExists)
+		assertSearch(t, store, "test", "this is just a test")
+	})
+
+	t.Run("update changes an existing word", func(t *testing.T) {
+		store := newStore()
+		store.Add("test", "this is just a test")
+
+		assertError(t, store.Update("test", "new definition"), nil)
+		assertSearch(t, store, "test", "new definition")
+	})
+
+	t.Run("update refuses a missing word", func(t *testing.T) {
+		store := newStore()
+
+		assertError(t, store.Update("test", "definition"), ErrWordDoesNotExists)
+	})
+
+	t.Run("delete removes a word", func(t *testing.T) {
+		store := newStore()
+		store.Add("test", "this is just a test")
+
+		store.Delete("test")
+
+		_, err := store.Search("test")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("search prefix lists matching words in order", func(t *testing.T) {
+		store := newStore()
+		for _, word := range []string{"test", "tester", "team", "testing", "toast"} {
+			store.Add(word, "definition")
+		}
+
+		assertWords(t, store.SearchPrefix("test"), []string{"test", "tester", "testing"})
+
+		store.Delete("tester")
+		assertWords(t, store.SearchPrefix("test"), []string{"test", "testing"})
+	})
+
+	t.Run("a missed search suggests close words", func(t *testing.T) {
+		store := newStore()
+		store.Add("test", "this is just a test")
+		store.Add("toast", "bread")
+
+		_, err := store.Search("tset")
+
+		var notFound *WordError
+		if !errors.As(err, &notFound) {
+			t.Fatalf("got %v want a *WordError", err)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected %v to match %v", err, ErrNotFound)
+		}
+		assertWords(t, notFound.Suggestions, []string{"test"})
+	})
+
+	if !concurrent {
+		return
+	}
+
+	t.Run("concurrent readers and writers", func(t *testing.T) {
+		store := newStore()
+		store.Add("shared", "v0")
+
+		const workers = 50
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				word := fmt.Sprintf("word-%d", i)
+				store.Add(word, "first")
+				store.Update(word, "second")
+				store.Search("shared")
+				store.Update("shared", fmt.Sprintf("v%d", i))
+				store.Add("shared", "clash")
+				store.SearchPrefix("word-")
+				store.Search("wrod-1")
+			}()
+		}
+		wg.Wait()
+
+		for i := 0; i < workers; i++ {
+			assertSearch(t, store, fmt.Sprintf("word-%d", i), "second")
+		}
+		if _, err := store.Search("shared"); err != nil {
+			t.Errorf("lost the shared word: %v", err)
+		}
+	})
+}
+
+func assertSearch(t testing.TB, store WordStore, word, want string) {
+	t.Helper()
+
+	got, err := store.Search(word)
+	if err != nil {
+		t.Fatal("should find added word:", err)
+	}
+	assertStrings(t, got, want)
+}
@@ -0,0 +1,152 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Bitcoin is an amount counted in whole satoshis, the smallest unit of
+// bitcoin, so fractions of a coin are exact.
+type Bitcoin int64
+
+const (
+	Satoshi Bitcoin = 1
+	BTC     Bitcoin = 100_000_000 * Satoshi
+
+	satoshiDigits = 8
+
+	maxBitcoin Bitcoin = math.MaxInt64
+)
+
+var (
+	ErrOverflow      = errors.New("bitcoin amount out of range")
+	ErrInvalidAmount = errors.New("invalid bitcoin amount")
+)
+
+// Add returns b+o, or ErrOverflow if the sum does not fit.
+func (b Bitcoin) Add(o Bitcoin) (Bitcoin, error) {
+	if (o > 0 && b > math.MaxInt64-o) || (o < 0 && b < math.MinInt64-o) {
+		return 0, ErrOverflow
+	}
+	return b + o, nil
+}
+
+// Sub returns b-o, or ErrOverflow if the difference does not fit.
+func (b Bitcoin) Sub(o Bitcoin) (Bitcoin, error) {
+	if (o < 0 && b > math.MaxInt64+o) || (o > 0 && b < math.MinInt64+o) {
+		return 0, ErrOverflow
+	}
+	return b - o, nil
+}
+
+// String prints the amount in BTC with as many decimals as it needs,
+// e.g. "10 BTC" or "0.0015 BTC".
+func (b Bitcoin) String() string {
+	return b.decimal() + " BTC"
+}
+
+func (b Bitcoin) decimal() string {
+	sign := ""
+	sats := uint64(b)
+	if b < 0 {
+		sign = "-"
+		sats = uint64(-b) // also right for MinInt64, whose negation wraps to itself
+	}
+
+	whole := sats / uint64(BTC)
+	frac := sats % uint64(BTC)
+	if frac == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	digits := strings.TrimRight(fmt.Sprintf("%0*d", satoshiDigits, frac), "0")
+	return fmt.Sprintf("%s%d.%s", sign, whole, digits)
+}
+
+// ParseBitcoin reads an amount in BTC such as "0.0015 BTC" or "-2". The
+// unit is optional and at most eight decimal places are allowed.
+func ParseBitcoin(s string) (Bitcoin, error) {
+	text := strings.TrimSpace(s)
+	text = strings.TrimSpace(strings.TrimSuffix(text, "BTC"))
+
+	sats, err := parseDecimal(text, satoshiDigits)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", err, s)
+	}
+	return Bitcoin(sats), nil
+}
+
+// parseDecimal reads a decimal such as "-12.5" as a count of units with
+// digits decimal places, 1250 for two. It takes only one optional sign and
+// plain digits, never exponents, fractions or hex, and returns a bare
+// ErrInvalidAmount or ErrOverflow for the caller to describe.
+func parseDecimal(text string, digits int) (int64, error) {
+	// at most one sign, so "-+5" is not read as -5
+	negative := strings.HasPrefix(text, "-")
+	if negative || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+
+	whole, frac, _ := strings.Cut(text, ".")
+	if whole == "" && frac == "" || len(frac) > digits || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+
+	// parse as one integer of units so nothing passes through a float
+	n, err := strconv.ParseUint(whole+frac, 10, 64)
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++ // MinInt64 is one further from zero than MaxInt64
+	}
+	if err != nil || n > limit {
+		return 0, ErrOverflow
+	}
+
+	if negative {
+		return -int64(n), nil
+	}
+	return int64(n), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON writes the amount as a string such as "0.0015 BTC", which
+// JavaScript clients cannot round into a float by accident.
+func (b Bitcoin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON accepts the string MarshalJSON writes, or a bare JSON
+// number of BTC. Either way the digits are parsed exactly. Like the
+// standard decoders it leaves b alone for a JSON null.
+func (b *Bitcoin) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	amount, err := ParseBitcoin(text)
+	if err != nil {
+		return err
+	}
+	*b = amount
+	return nil
+}
@@ -0,0 +1,182 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBitcoinString(t *testing.T) {
+	cases := []struct {
+		amount Bitcoin
+		want   string
+	}{
+		{10 * BTC, "10 BTC"},
+		{0, "0 BTC"},
+		{150_000 * Satoshi, "0.0015 BTC"},
+		{Satoshi, "0.00000001 BTC"},
+		{BTC + BTC/2, "1.5 BTC"},
+		{-BTC / 2, "-0.5 BTC"},
+		{math.MaxInt64, "92233720368.54775807 BTC"},
+		{math.MinInt64, "-92233720368.54775808 BTC"},
+	}
+
+	for _, tt := range cases {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Bitcoin(%d).String() = %q want %q", int64(tt.amount), got, tt.want)
+		}
+	}
+}
+
+func TestParseBitcoin(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Bitcoin
+	}{
+		{"0.0015 BTC", 150_000 * Satoshi},
+		{"10 BTC", 10 * BTC},
+		{"10", 10 * BTC},
+		{".5", BTC / 2},
+		{"-0.5 BTC", -BTC / 2},
+		{"+2", 2 * BTC},
+		{"0.00000001", Satoshi},
+		{"0", 0},
+		{"92233720368.54775807", math.MaxInt64},
+		{"-92233720368.54775808", math.MinInt64},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseBitcoin(tt.in)
+			assertNoError(t, err)
+			if got != tt.want {
+				t.Errorf("expected %s got %s", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("round trips through String", func(t *testing.T) {
+		for _, amount := range []Bitcoin{0, Satoshi, 123_456_789, -BTC, math.MaxInt64, math.MinInt64} {
+			got, err := ParseBitcoin(amount.String())
+			assertNoError(t, err)
+			if got != amount {
+				t.Errorf("expected %s got %s", amount, got)
+			}
+		}
+	})
+
+	errorCases := []struct {
+		in   string
+		want error
+	}{
+		{"", ErrInvalidAmount},
+		{"BTC", ErrInvalidAmount},
+		{".", ErrInvalidAmount},
+		{"0.000000001", ErrInvalidAmount},
+		{"1e3", ErrInvalidAmount},
+		{"ten", ErrInvalidAmount},
+		{"1.2.3", ErrInvalidAmount},
+		{"-+5", ErrInvalidAmount},
+		{"+-5", ErrInvalidAmount},
+		{"--5", ErrInvalidAmount},
+		{"92233720368.54775808", ErrOverflow},
+		{"-92233720368.54775809", ErrOverflow},
+		{"99999999999999999999999", ErrOverflow},
+	}
+
+	for _, tt := range errorCases {
+		t.Run("rejects "+tt.in, func(t *testing.T) {
+			_, err := ParseBitcoin(tt.in)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestBitcoinArithmetic(t *testing.T) {
+	t.Run("adds and subtracts", func(t *testing.T) {
+		sum, err := BTC.Add(Satoshi)
+		assertNoError(t, err)
+		if sum != 100_000_001 {
+			t.Errorf("expected %d got %d", 100_000_001, sum)
+		}
+
+		diff, err := BTC.Sub(2 * BTC)
+		assertNoError(t, err)
+		if diff != -BTC {
+			t.Errorf("expected %s got %s", -BTC, diff)
+		}
+	})
+
+	t.Run("detects overflow", func(t *testing.T) {
+		if _, err := Bitcoin(math.MaxInt64).Add(Satoshi); !errors.Is(err, ErrOverflow) {
+			t.Errorf("expected %v got %v", ErrOverflow, err)
+		}
+		if _, err := Bitcoin(math.MinInt64).Sub(Satoshi); !errors.Is(err, ErrOverflow) {
+			t.Errorf("expected %v got %v", ErrOverflow, err)
+		}
+		if _, err := Bitcoin(math.MinInt64).Add(-Satoshi); !errors.Is(err, ErrOverflow) {
+			t.Errorf("expected %v got %v", ErrOverflow, err)
+		}
+	})
+
+	t.Run("a wallet refuses a deposit that would overflow", func(t *testing.T) {
+		wallet := Wallet{balance: Bitcoin(math.MaxInt64)}
+
+		err := wallet.Deposit(Satoshi)
+
+		assertError(t, err, ErrOverflow)
+		assertBalance(t, &wallet, Bitcoin(math.MaxInt64))
+	})
+}
+
+func TestBitcoinJSON(t *testing.T) {
+	type payment struct {
+		Amount Bitcoin `json:"amount"`
+	}
+
+	t.Run("round trips exactly", func(t *testing.T) {
+		want := payment{Bitcoin(123_456_789)}
+
+		data, err := json.Marshal(want)
+		assertNoError(t, err)
+		if string(data) != `{"amount":"1.23456789 BTC"}` {
+			t.Errorf("unexpected JSON %s", data)
+		}
+
+		var got payment
+		assertNoError(t, json.Unmarshal(data, &got))
+		if got != want {
+			t.Errorf("expected %s got %s", want.Amount, got.Amount)
+		}
+	})
+
+	t.Run("accepts a bare number without going through a float", func(t *testing.T) {
+		var got payment
+		assertNoError(t, json.Unmarshal([]byte(`{"amount": 0.1}`), &got))
+
+		if got.Amount != BTC/10 {
+			t.Errorf("expected %s got %s", BTC/10, got.Amount)
+		}
+	})
+
+	t.Run("leaves the amount alone for null", func(t *testing.T) {
+		got := payment{BTC}
+		assertNoError(t, json.Unmarshal([]byte(`{"amount": null}`), &got))
+
+		if got.Amount != BTC {
+			t.Errorf("expected %s got %s", BTC, got.Amount)
+		}
+	})
+
+	t.Run("rejects malformed amounts", func(t *testing.T) {
+		var got payment
+		err := json.Unmarshal([]byte(`{"amount": "lots"}`), &got)
+
+		if !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("expected %v got %v", ErrInvalidAmount, err)
+		}
+	})
+}
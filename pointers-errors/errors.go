@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrZeroAmount     = errors.New("amount must not be zero")
+	ErrNegativeAmount = errors.New("amount must not be negative")
+)
+
+// InsufficientFundsError says how far short a withdrawal or transfer fell.
+// It matches ErrInsufficientFunds with errors.Is.
+type InsufficientFundsError struct {
+	Requested Bitcoin
+	Available Bitcoin
+}
+
+// Shortfall is how much more the wallet would have needed.
+func (e *InsufficientFundsError) Shortfall() Bitcoin {
+	return e.Requested - e.Available
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%v: requested %s, available %s, short by %s",
+		ErrInsufficientFunds, e.Requested, e.Available, e.Shortfall())
+}
+
+func (e *InsufficientFundsError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+// InsufficientMoneyError is InsufficientFundsError for a MultiWallet, in
+// the currency of the withdrawal. It also matches ErrInsufficientFunds.
+type InsufficientMoneyError struct {
+	Requested Money
+	Available Money
+}
+
+// Shortfall is how much more of the currency the wallet would have needed.
+func (e *InsufficientMoneyError) Shortfall() Money {
+	return Money{e.Requested.Minor - e.Available.Minor, e.Requested.Currency}
+}
+
+func (e *InsufficientMoneyError) Error() string {
+	return fmt.Sprintf("%v: requested %s, available %s, short by %s",
+		ErrInsufficientFunds, e.Requested, e.Available, e.Shortfall())
+}
+
+func (e *InsufficientMoneyError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+// AmountError rejects an amount before it reaches the balance. It unwraps
+// to ErrZeroAmount or ErrNegativeAmount.
+type AmountError struct {
+	Op     string
+	Amount Bitcoin
+	Err    error
+}
+
+func (e *AmountError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Amount, e.Err)
+}
+
+func (e *AmountError) Unwrap() error {
+	return e.Err
+}
+
+func validateAmount(op string, amount Bitcoin) error {
+	switch {
+	case amount == 0:
+		return &AmountError{op, amount, ErrZeroAmount}
+	case amount < 0:
+		return &AmountError{op, amount, ErrNegativeAmount}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInsufficientFundsError(t *testing.T) {
+	wallet := &Wallet{}
+	wallet.Deposit(20 * BTC)
+
+	err := wallet.Withdraw(100 * BTC)
+
+	var got *InsufficientFundsError
+	if !errors.As(err, &got) {
+		t.Fatalf("expected an *InsufficientFundsError got %v", err)
+	}
+	assertError(t, err, ErrInsufficientFunds)
+
+	if got.Requested != 100*BTC || got.Available != 20*BTC || got.Shortfall() != 80*BTC {
+		t.Errorf("unexpected amounts in %+v", got)
+	}
+	want := "cannot withdraw, insufficient funds: requested 100 BTC, available 20 BTC, short by 80 BTC"
+	if err.Error() != want {
+		t.Errorf("expected %q got %q", want, err)
+	}
+
+	t.Run("from a transfer", func(t *testing.T) {
+		err := Transfer(wallet, &Wallet{}, 25*BTC)
+
+		var got *InsufficientFundsError
+		if !errors.As(err, &got) || got.Shortfall() != 5*BTC {
+			t.Errorf("expected a shortfall of %s got %v", 5*BTC, err)
+		}
+	})
+}
+
+func TestAmountValidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount Bitcoin
+		want   error
+	}{
+		{"zero", 0, ErrZeroAmount},
+		{"negative", -5 * BTC, ErrNegativeAmount},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &Wallet{}
+			wallet.Deposit(10 * BTC)
+			other := &Wallet{}
+
+			assertError(t, wallet.Deposit(tt.amount), tt.want)
+			assertError(t, wallet.Withdraw(tt.amount), tt.want)
+			assertError(t, Transfer(wallet, other, tt.amount), tt.want)
+
+			assertBalance(t, wallet, 10*BTC)
+			assertBalance(t, other, 0)
+			if len(wallet.Ledger()) != 1 {
+				t.Errorf("rejected amounts were recorded: %+v", wallet.Ledger())
+			}
+		})
+	}
+
+	t.Run("the error names the operation", func(t *testing.T) {
+		err := (&Wallet{}).Deposit(Bitcoin(-5))
+
+		want := "deposit -0.00000005 BTC: amount must not be negative"
+		if err.Error() != want {
+			t.Errorf("expected %q got %q", want, err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultHoldTTL is how long a hold lasts when the wallet's HoldTTL is unset.
+const DefaultHoldTTL = 24 * time.Hour
+
+var (
+	ErrHoldNotFound = errors.New("no such hold on this wallet")
+	ErrHoldExpired  = errors.New("hold has expired")
+)
+
+// HoldID identifies a hold within one wallet.
+type HoldID uint64
+
+type hold struct {
+	amount  Bitcoin
+	expires time.Time
+}
+
+// Available is what can still be withdrawn or held: the balance plus the
+// overdraft limit, less any unexpired holds.
+func (w *Wallet) Available() Bitcoin {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.available()
+}
+
+// available must be called with w.mu held. It saturates rather than
+// overflowing, as no single amount can exceed it anyway.
+func (w *Wallet) available() Bitcoin {
+	available, err := w.balance.Add(max(w.OverdraftLimit, 0))
+	if err != nil {
+		available = maxBitcoin
+	}
+
+	if len(w.holds) == 0 {
+		return available
+	}
+	now := w.now()
+	for _, h := range w.holds {
+		if now.Before(h.expires) {
+			available -= h.amount
+		}
+	}
+	return available
+}
+
+// Hold reserves amount so it cannot be withdrawn by anyone else. The
+// balance is unchanged until the hold is captured; until then it only
+// reduces Available. Holds expire after HoldTTL on the wallet's clock.
+func (w *Wallet) Hold(amount Bitcoin) (HoldID, error) {
+	if err := validateAmount("hold", amount); err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.sweepHolds()
+	if available := w.available(); amount > available {
+		return 0, &InsufficientFundsError{Requested: amount, Available: available}
+	}
+
+	ttl := w.HoldTTL
+	if ttl <= 0 {
+		ttl = DefaultHoldTTL
+	}
+	if w.holds == nil {
+		w.holds = map[HoldID]hold{}
+	}
+	w.lastHold++
+	w.holds[w.lastHold] = hold{amount, w.now().Add(ttl)}
+	return w.lastHold, nil
+}
+
+// Capture turns a hold into a withdrawal of the held amount.
+func (w *Wallet) Capture(id HoldID) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	h, err := w.takeHold(id)
+	if err != nil {
+		return err
+	}
+	// the hold already came out of Available, so this cannot fail the
+	// overdraft check; only an overflow of the balance itself is possible
+	balance, err := w.balance.Sub(h.amount)
+	if err != nil {
+		return err
+	}
+	w.post(EntryCapture, h.amount, balance)
+	return nil
+}
+
+// Release drops a hold without charging it.
+func (w *Wallet) Release(id HoldID) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.takeHold(id)
+	return err
+}
+
+// takeHold removes the hold, reporting ErrHoldExpired if it ran out
+// first. Expired holds are forgotten once taken or swept, after which
+// they are ErrHoldNotFound.
+func (w *Wallet) takeHold(id HoldID) (hold, error) {
+	h, ok := w.holds[id]
+	if !ok {
+		return hold{}, ErrHoldNotFound
+	}
+	delete(w.holds, id)
+	if !w.now().Before(h.expires) {
+		return hold{}, ErrHoldExpired
+	}
+	return h, nil
+}
+
+// sweepHolds forgets expired holds so the map does not grow forever.
+func (w *Wallet) sweepHolds() {
+	now := w.now()
+	for id, h := range w.holds {
+		if !now.Before(h.expires) {
+			delete(w.holds, id)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOverdraft(t *testing.T) {
+	t.Run("withdraws below zero up to the limit", func(t *testing.T) {
+		wallet := &Wallet{OverdraftLimit: 5 * BTC}
+		wallet.Deposit(10 * BTC)
+
+		assertNoError(t, wallet.Withdraw(14*BTC))
+		assertBalance(t, wallet, -4*BTC)
+
+		err := wallet.Withdraw(2 * BTC)
+		assertError(t, err, ErrInsufficientFunds)
+		assertBalance(t, wallet, -4*BTC)
+
+		var short *InsufficientFundsError
+		if errors.As(err, &short) && short.Available != BTC {
+			t.Errorf("expected %s available got %s", BTC, short.Available)
+		}
+	})
+
+	t.Run("the ledger still replays", func(t *testing.T) {
+		wallet := &Wallet{OverdraftLimit: 5 * BTC}
+		wallet.Deposit(BTC)
+		wallet.Withdraw(3 * BTC)
+
+		got, err := Replay(wallet.Ledger())
+		assertNoError(t, err)
+		if got != -2*BTC {
+			t.Errorf("expected %s got %s", -2*BTC, got)
+		}
+	})
+}
+
+// manualClock only moves when told to, for tests that care how much time
+// passes between two calls.
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestHolds(t *testing.T) {
+	newHoldWallet := func() (*Wallet, *manualClock) {
+		clock := &manualClock{ledgerStart}
+		wallet := &Wallet{Now: clock.Now}
+		wallet.HoldTTL = time.Hour
+		wallet.Deposit(10 * BTC)
+		return wallet, clock
+	}
+
+	t.Run("a hold reduces available but not the balance", func(t *testing.T) {
+		wallet, _ := newHoldWallet()
+
+		_, err := wallet.Hold(4 * BTC)
+
+		assertNoError(t, err)
+		assertBalance(t, wallet, 10*BTC)
+		assertAvailable(t, wallet, 6*BTC)
+		assertError(t, wallet.Withdraw(7*BTC), ErrInsufficientFunds)
+		assertNoError(t, wallet.Withdraw(6*BTC))
+	})
+
+	t.Run("capturing a hold withdraws it", func(t *testing.T) {
+		wallet, _ := newHoldWallet()
+		id, _ := wallet.Hold(4 * BTC)
+
+		assertNoError(t, wallet.Capture(id))
+
+		assertBalance(t, wallet, 6*BTC)
+		assertAvailable(t, wallet, 6*BTC)
+		last := wallet.Ledger()[len(wallet.Ledger())-1]
+		if last.Kind != EntryCapture || last.Amount != 4*BTC {
+			t.Errorf("expected a capture of %s got %+v", 4*BTC, last)
+		}
+		assertError(t, wallet.Capture(id), ErrHoldNotFound)
+	})
+
+	t.Run("releasing a hold frees the funds", func(t *testing.T) {
+		wallet, _ := newHoldWallet()
+		id, _ := wallet.Hold(4 * BTC)
+
+		assertNoError(t, wallet.Release(id))
+
+		assertBalance(t, wallet, 10*BTC)
+		assertAvailable(t, wallet, 10*BTC)
+		assertError(t, wallet.Release(id), ErrHoldNotFound)
+		if len(wallet.Ledger()) != 1 {
+			t.Errorf("a release should not touch the ledger: %+v", wallet.Ledger())
+		}
+	})
+
+	t.Run("cannot hold more than is available", func(t *testing.T) {
+		wallet, _ := newHoldWallet()
+		wallet.Hold(8 * BTC)
+
+		_, err := wallet.Hold(3 * BTC)
+
+		assertError(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("holds can use the overdraft", func(t *testing.T) {
+		wallet, _ := newHoldWallet()
+		wallet.OverdraftLimit = 2 * BTC
+
+		id, err := wallet.Hold(12 * BTC)
+		assertNoError(t, err)
+		assertNoError(t, wallet.Capture(id))
+
+		assertBalance(t, wallet, -2*BTC)
+	})
+
+	t.Run("holds expire after the TTL", func(t *testing.T) {
+		wallet, clock := newHoldWallet()
+		id, _ := wallet.Hold(4 * BTC)
+
+		clock.advance(59 * time.Minute)
+		assertAvailable(t, wallet, 6*BTC)
+
+		clock.advance(time.Minute)
+		assertAvailable(t, wallet, 10*BTC)
+		assertError(t, wallet.Capture(id), ErrHoldExpired)
+		assertBalance(t, wallet, 10*BTC)
+	})
+
+	t.Run("rejects invalid amounts", func(t *testing.T) {
+		wallet, _ := newHoldWallet()
+
+		_, err := wallet.Hold(0)
+
+		assertError(t, err, ErrZeroAmount)
+	})
+}
+
+func assertAvailable(t testing.TB, wallet *Wallet, want Bitcoin) {
+	t.Helper()
+	if got := wallet.Available(); got != want {
+		t.Errorf("expected %s available got %s", want, got)
+	}
+}
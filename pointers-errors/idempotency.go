@@ -0,0 +1,93 @@
+package wallet
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	errKeyInUse  = errors.New("a request with this Idempotency-Key is still being processed")
+	errKeyReused = errors.New("this Idempotency-Key was used for a different request")
+)
+
+// idempotencySweep is how often expired keys are cleared out. Between
+// sweeps an expired key is still treated as gone when it is looked up.
+const idempotencySweep = time.Minute
+
+// idempotencyCache remembers the response to each Idempotency-Key along
+// with a fingerprint of the request that produced it. It lives only in
+// memory, so keys do not survive a restart of the server.
+type idempotencyCache struct {
+	mu        sync.Mutex
+	entries   map[string]*idempotentEntry
+	lastSweep time.Time
+}
+
+type idempotentEntry struct {
+	fingerprint [32]byte
+	done        bool
+	res         response
+	expires     time.Time
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{entries: map[string]*idempotentEntry{}}
+}
+
+// begin claims key for a request. It returns the kept response if the
+// same request already finished, errKeyInUse if it is still running and
+// errKeyReused if the key belongs to a different request. Otherwise it
+// returns nil and the caller must call finish.
+func (c *idempotencyCache) begin(key string, fingerprint [32]byte, now time.Time) (*response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) >= idempotencySweep {
+		for k, e := range c.entries {
+			if e.expired(now) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	e, ok := c.entries[key]
+	switch {
+	case !ok, e.expired(now):
+		c.entries[key] = &idempotentEntry{fingerprint: fingerprint}
+		return nil, nil
+	case e.fingerprint != fingerprint:
+		return nil, errKeyReused
+	case !e.done:
+		return nil, errKeyInUse
+	}
+	res := e.res
+	return &res, nil
+}
+
+// finish keeps res for key until expires. Server errors are kept too: a
+// failed journal write may still have reached the disk, so running the
+// request again could apply it twice.
+func (c *idempotencyCache) finish(key string, res response, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.entries[key]
+	e.done, e.res, e.expires = true, res, expires
+}
+
+// abandon forgets key if its request never finished, so a handler that
+// panicked does not leave the key claimed for good.
+func (c *idempotencyCache) abandon(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok && !e.done {
+		delete(c.entries, key)
+	}
+}
+
+func (e *idempotentEntry) expired(now time.Time) bool {
+	return e.done && !now.Before(e.expires)
+}
@@ -0,0 +1,171 @@
+package wallet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+type EntryKind int
+
+const (
+	EntryDeposit EntryKind = iota
+	EntryWithdrawal
+	EntryTransferIn
+	EntryTransferOut
+	EntryCapture
+)
+
+func (k EntryKind) String() string {
+	switch k {
+	case EntryDeposit:
+		return "deposit"
+	case EntryWithdrawal:
+		return "withdrawal"
+	case EntryTransferIn:
+		return "transfer in"
+	case EntryTransferOut:
+		return "transfer out"
+	case EntryCapture:
+		return "capture"
+	}
+	return fmt.Sprintf("EntryKind(%d)", int(k))
+}
+
+// credit reports whether entries of this kind add to the balance.
+func (k EntryKind) credit() bool {
+	return k == EntryDeposit || k == EntryTransferIn
+}
+
+// Entry is one change to a wallet's balance. Amount is always positive;
+// Kind says which way it went. Balance is the balance after the entry.
+type Entry struct {
+	ID      uint64
+	Time    time.Time
+	Kind    EntryKind
+	Amount  Bitcoin
+	Balance Bitcoin
+}
+
+var ErrLedgerMismatch = errors.New("ledger entry does not follow from the one before it")
+
+// post records a change to the balance. It must be called with w.mu held.
+func (w *Wallet) post(kind EntryKind, amount, balance Bitcoin) {
+	w.apply(w.entry(kind, amount, balance))
+}
+
+// entry builds the next ledger entry without posting it, so a WalletStore
+// can journal it first. It must be called with w.mu held.
+func (w *Wallet) entry(kind EntryKind, amount, balance Bitcoin) Entry {
+	return Entry{
+		ID:      uint64(len(w.ledger)) + 1,
+		Time:    w.now(),
+		Kind:    kind,
+		Amount:  amount,
+		Balance: balance,
+	}
+}
+
+// apply appends e and moves the balance to match it. It is the only place
+// the balance changes. It must be called with w.mu held.
+func (w *Wallet) apply(e Entry) {
+	w.ledger = append(w.ledger, e)
+	w.balance = e.Balance
+}
+
+func (w *Wallet) now() time.Time {
+	if w.Now == nil {
+		return time.Now()
+	}
+	return w.Now()
+}
+
+// Ledger returns a copy of every entry, oldest first.
+func (w *Wallet) Ledger() []Entry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Entry(nil), w.ledger...)
+}
+
+// Replay rebuilds a balance from an empty wallet by applying entries in
+// order, checking each entry's Balance along the way.
+func Replay(entries []Entry) (Bitcoin, error) {
+	var balance Bitcoin
+	for _, e := range entries {
+		var err error
+		if e.Kind.credit() {
+			balance, err = balance.Add(e.Amount)
+		} else {
+			balance, err = balance.Sub(e.Amount)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("entry %d: %w", e.ID, err)
+		}
+		if balance != e.Balance {
+			return 0, fmt.Errorf("%w: entry %d has balance %s, replay gives %s", ErrLedgerMismatch, e.ID, e.Balance, balance)
+		}
+	}
+	return balance, nil
+}
+
+type StatementFormat int
+
+const (
+	StatementTable StatementFormat = iota
+	StatementCSV
+)
+
+// Statement writes the entries made at or after from and before to.
+func (w *Wallet) Statement(out io.Writer, from, to time.Time, format StatementFormat) error {
+	var entries []Entry
+	for _, e := range w.Ledger() {
+		if !e.Time.Before(from) && e.Time.Before(to) {
+			entries = append(entries, e)
+		}
+	}
+
+	switch format {
+	case StatementTable:
+		return writeStatementTable(out, entries)
+	case StatementCSV:
+		return writeStatementCSV(out, entries)
+	}
+	return fmt.Errorf("unknown statement format %d", format)
+}
+
+func writeStatementTable(out io.Writer, entries []Entry) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "ID\tTime\tKind\tAmount\tBalance\t")
+	for _, e := range entries {
+		amount := e.Amount.String()
+		if !e.Kind.credit() {
+			amount = "-" + amount
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t\n", e.ID, e.Time.Format(time.RFC3339), e.Kind, amount, e.Balance)
+	}
+	return tw.Flush()
+}
+
+func writeStatementCSV(out io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(out)
+	cw.Write([]string{"id", "time", "kind", "amount", "balance"})
+	for _, e := range entries {
+		amount := e.Amount.decimal()
+		if !e.Kind.credit() {
+			amount = "-" + amount
+		}
+		cw.Write([]string{
+			strconv.FormatUint(e.ID, 10),
+			e.Time.Format(time.RFC3339Nano),
+			e.Kind.String(),
+			amount,
+			e.Balance.decimal(),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,157 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var ledgerStart = time.Date(2024, time.September, 5, 9, 0, 0, 0, time.UTC)
+
+// stubClock moves forward a minute every time it is read.
+type stubClock struct {
+	now time.Time
+}
+
+func (c *stubClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(time.Minute)
+	return now
+}
+
+func newLedgerWallet() *Wallet {
+	clock := &stubClock{ledgerStart}
+	return &Wallet{Now: clock.Now}
+}
+
+func TestLedger(t *testing.T) {
+	t.Run("records deposits and withdrawals", func(t *testing.T) {
+		wallet := newLedgerWallet()
+		wallet.Deposit(10 * BTC)
+		wallet.Withdraw(3 * BTC)
+		wallet.Withdraw(100 * BTC) // refused, so not recorded
+
+		want := []Entry{
+			{ID: 1, Time: ledgerStart, Kind: EntryDeposit, Amount: 10 * BTC, Balance: 10 * BTC},
+			{ID: 2, Time: ledgerStart.Add(time.Minute), Kind: EntryWithdrawal, Amount: 3 * BTC, Balance: 7 * BTC},
+		}
+		assertLedger(t, wallet.Ledger(), want)
+	})
+
+	t.Run("records both sides of a transfer", func(t *testing.T) {
+		from, to := newLedgerWallet(), newLedgerWallet()
+		from.Deposit(5 * BTC)
+
+		Transfer(from, to, 2*BTC)
+
+		assertLedger(t, from.Ledger()[1:], []Entry{
+			{ID: 2, Time: ledgerStart.Add(time.Minute), Kind: EntryTransferOut, Amount: 2 * BTC, Balance: 3 * BTC},
+		})
+		assertLedger(t, to.Ledger(), []Entry{
+			{ID: 1, Time: ledgerStart, Kind: EntryTransferIn, Amount: 2 * BTC, Balance: 2 * BTC},
+		})
+	})
+
+	t.Run("returned entries cannot change the ledger", func(t *testing.T) {
+		wallet := newLedgerWallet()
+		wallet.Deposit(BTC)
+
+		wallet.Ledger()[0].Amount = 1000 * BTC
+
+		if got := wallet.Ledger()[0].Amount; got != BTC {
+			t.Errorf("expected %s got %s", BTC, got)
+		}
+	})
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("replaying the ledger gives the balance", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(1, 2))
+		a, b := newLedgerWallet(), newLedgerWallet()
+
+		for i := 0; i < 1000; i++ {
+			amount := Bitcoin(rng.Int64N(int64(5 * BTC)))
+			switch rng.IntN(4) {
+			case 0:
+				a.Deposit(amount)
+			case 1:
+				a.Withdraw(amount)
+			case 2:
+				Transfer(a, b, amount)
+			case 3:
+				Transfer(b, a, amount)
+			}
+		}
+
+		for _, wallet := range []*Wallet{a, b} {
+			got, err := Replay(wallet.Ledger())
+			assertNoError(t, err)
+			if got != wallet.Balance() {
+				t.Errorf("replay gives %s, balance is %s", got, wallet.Balance())
+			}
+		}
+	})
+
+	t.Run("detects a tampered entry", func(t *testing.T) {
+		wallet := newLedgerWallet()
+		wallet.Deposit(10 * BTC)
+		wallet.Withdraw(3 * BTC)
+
+		entries := wallet.Ledger()
+		entries[0].Amount = 20 * BTC
+
+		_, err := Replay(entries)
+		if !errors.Is(err, ErrLedgerMismatch) {
+			t.Errorf("expected %v got %v", ErrLedgerMismatch, err)
+		}
+	})
+}
+
+func TestStatement(t *testing.T) {
+	wallet := newLedgerWallet()
+	wallet.Deposit(10 * BTC)          // 09:00
+	wallet.Withdraw(BTC / 4)          // 09:01
+	wallet.Deposit(150_000 * Satoshi) // 09:02
+	wallet.Withdraw(2 * BTC)          // 09:03
+
+	from := ledgerStart.Add(time.Minute)
+	to := ledgerStart.Add(3 * time.Minute)
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		assertNoError(t, wallet.Statement(&buf, from, to, StatementTable))
+
+		want := "" +
+			"  ID                  Time        Kind      Amount     Balance\n" +
+			"   2  2024-09-05T09:01:00Z  withdrawal   -0.25 BTC    9.75 BTC\n" +
+			"   3  2024-09-05T09:02:00Z     deposit  0.0015 BTC  9.7515 BTC\n"
+		assertStatement(t, buf.String(), want)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		assertNoError(t, wallet.Statement(&buf, from, to, StatementCSV))
+
+		want := "id,time,kind,amount,balance\n" +
+			"2,2024-09-05T09:01:00Z,withdrawal,-0.25,9.75\n" +
+			"3,2024-09-05T09:02:00Z,deposit,0.0015,9.7515\n"
+		assertStatement(t, buf.String(), want)
+	})
+}
+
+func assertLedger(t testing.TB, got, want []Entry) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected ledger %+v got %+v", want, got)
+	}
+}
+
+func assertStatement(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("expected statement\n%s\ngot\n%s", want, got)
+	}
+}
@@ -0,0 +1,139 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Currency is an upper case currency code such as "BTC" or "USD".
+type Currency string
+
+const (
+	CurrencyBTC Currency = "BTC"
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+	CurrencyGBP Currency = "GBP"
+	CurrencyJPY Currency = "JPY"
+)
+
+// minorDigits is how many decimal places each currency's smallest unit is.
+var minorDigits = map[Currency]int{
+	CurrencyBTC: satoshiDigits,
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyGBP: 2,
+	CurrencyJPY: 0,
+}
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("cannot combine amounts in different currencies")
+)
+
+// Digits is the number of decimal places in the currency's minor unit.
+func (c Currency) Digits() (int, error) {
+	digits, ok := minorDigits[c]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, string(c))
+	}
+	return digits, nil
+}
+
+// Money is an amount of a currency counted in its minor unit: cents for
+// USD, satoshis for BTC. Amounts in different currencies never mix;
+// arithmetic on them returns ErrCurrencyMismatch.
+type Money struct {
+	Minor    int64
+	Currency Currency
+}
+
+// NewMoney parses a decimal amount such as "12.50" in currency.
+func NewMoney(amount string, currency Currency) (Money, error) {
+	digits, err := currency.Digits()
+	if err != nil {
+		return Money{}, err
+	}
+	minor, err := parseMinor(amount, digits)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor, currency}, nil
+}
+
+// parseMinor turns a decimal string into an integer of minor units with
+// the same parser as ParseBitcoin.
+func parseMinor(amount string, digits int) (int64, error) {
+	minor, err := parseDecimal(strings.TrimSpace(amount), digits)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", err, amount)
+	}
+	return minor, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// FromBitcoin expresses a Bitcoin amount as Money.
+func FromBitcoin(b Bitcoin) Money {
+	return Money{int64(b), CurrencyBTC}
+}
+
+// Bitcoin converts BTC money back, refusing any other currency.
+func (m Money) Bitcoin() (Bitcoin, error) {
+	if m.Currency != CurrencyBTC {
+		return 0, m.mismatch(CurrencyBTC)
+	}
+	return Bitcoin(m.Minor), nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, m.mismatch(o.Currency)
+	}
+	sum, err := Bitcoin(m.Minor).Add(Bitcoin(o.Minor))
+	return Money{int64(sum), m.Currency}, err
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, m.mismatch(o.Currency)
+	}
+	diff, err := Bitcoin(m.Minor).Sub(Bitcoin(o.Minor))
+	return Money{int64(diff), m.Currency}, err
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, m.mismatch(o.Currency)
+	}
+	switch {
+	case m.Minor < o.Minor:
+		return -1, nil
+	case m.Minor > o.Minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) mismatch(other Currency) error {
+	return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other)
+}
+
+// String prints the amount with the currency's decimal places trimmed of
+// trailing zeros, e.g. "12.5 USD" or "0.0015 BTC".
+func (m Money) String() string {
+	digits, err := m.Currency.Digits()
+	if err != nil {
+		return fmt.Sprintf("%d (minor units) %s", m.Minor, m.Currency)
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(m.Minor), pow10(digits))
+	text := r.FloatString(digits)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return text + " " + string(m.Currency)
+}
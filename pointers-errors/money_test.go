@@ -0,0 +1,200 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run("parses and prints in the currency's minor units", func(t *testing.T) {
+		cases := []struct {
+			amount   string
+			currency Currency
+			minor    int64
+			want     string
+		}{
+			{"12.50", CurrencyUSD, 1250, "12.5 USD"},
+			{"0.0015", CurrencyBTC, 150000, "0.0015 BTC"},
+			{"300", CurrencyJPY, 300, "300 JPY"},
+		}
+
+		for _, tt := range cases {
+			got, err := NewMoney(tt.amount, tt.currency)
+			assertNoError(t, err)
+			if got.Minor != tt.minor || got.String() != tt.want {
+				t.Errorf("expected %d (%s) got %d (%s)", tt.minor, tt.want, got.Minor, got)
+			}
+		}
+	})
+
+	t.Run("rejects amounts finer than the minor unit", func(t *testing.T) {
+		_, err := NewMoney("0.001", CurrencyUSD)
+		assertError(t, err, ErrInvalidAmount)
+	})
+
+	t.Run("rejects anything but plain decimals", func(t *testing.T) {
+		for _, amount := range []string{"1/4", "1e2", "0x10", "-+5", "1_000"} {
+			_, err := NewMoney(amount, CurrencyUSD)
+			assertError(t, err, ErrInvalidAmount)
+		}
+	})
+
+	t.Run("rejects unknown currencies", func(t *testing.T) {
+		_, err := NewMoney("1", Currency("XYZ"))
+		assertError(t, err, ErrUnknownCurrency)
+	})
+
+	t.Run("adds and subtracts the same currency", func(t *testing.T) {
+		sum, err := Money{1250, CurrencyUSD}.Add(Money{50, CurrencyUSD})
+		assertNoError(t, err)
+		assertMoney(t, sum, Money{1300, CurrencyUSD})
+
+		diff, err := sum.Sub(Money{300, CurrencyUSD})
+		assertNoError(t, err)
+		assertMoney(t, diff, Money{1000, CurrencyUSD})
+	})
+
+	t.Run("refuses to mix currencies", func(t *testing.T) {
+		usd, eur := Money{100, CurrencyUSD}, Money{100, CurrencyEUR}
+
+		_, err := usd.Add(eur)
+		assertError(t, err, ErrCurrencyMismatch)
+		_, err = usd.Sub(eur)
+		assertError(t, err, ErrCurrencyMismatch)
+		_, err = usd.Cmp(eur)
+		assertError(t, err, ErrCurrencyMismatch)
+		_, err = usd.Bitcoin()
+		assertError(t, err, ErrCurrencyMismatch)
+	})
+
+	t.Run("round trips Bitcoin", func(t *testing.T) {
+		got, err := FromBitcoin(3 * BTC).Bitcoin()
+		assertNoError(t, err)
+		if got != 3*BTC {
+			t.Errorf("expected %s got %s", 3*BTC, got)
+		}
+	})
+}
+
+func TestConvert(t *testing.T) {
+	rates := NewStaticRates()
+	assertNoError(t, rates.Set(CurrencyBTC, CurrencyUSD, "60000"))
+	assertNoError(t, rates.Set(CurrencyUSD, CurrencyJPY, "150.25"))
+
+	cases := []struct {
+		name string
+		from Money
+		to   Currency
+		want Money
+	}{
+		{"at the set rate", Money{int64(BTC / 2), CurrencyBTC}, CurrencyUSD, Money{3000000, CurrencyUSD}},
+		{"at the inverse rate", Money{6000, CurrencyUSD}, CurrencyBTC, Money{100000, CurrencyBTC}},
+		{"rounding half away from zero", Money{1, CurrencyUSD}, CurrencyJPY, Money{2, CurrencyJPY}},
+		{"to the same currency", Money{42, CurrencyUSD}, CurrencyUSD, Money{42, CurrencyUSD}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.from, tt.to, rates)
+			assertNoError(t, err)
+			assertMoney(t, got, tt.want)
+		})
+	}
+
+	t.Run("without a rate", func(t *testing.T) {
+		_, err := Convert(Money{100, CurrencyEUR}, CurrencyGBP, rates)
+		assertError(t, err, ErrNoRate)
+	})
+
+	t.Run("rejects non-positive rates", func(t *testing.T) {
+		assertError(t, rates.Set(CurrencyEUR, CurrencyGBP, "0"), ErrInvalidAmount)
+	})
+}
+
+func TestMultiWallet(t *testing.T) {
+	rates := NewStaticRates()
+	assertNoError(t, rates.Set(CurrencyUSD, CurrencyEUR, "0.9"))
+	btcRates := NewStaticRates()
+	assertNoError(t, btcRates.Set(CurrencyBTC, CurrencyUSD, "1000000000"))
+
+	t.Run("keeps a balance per currency", func(t *testing.T) {
+		wallet := &MultiWallet{}
+		assertNoError(t, wallet.Deposit(Money{1000, CurrencyUSD}))
+		assertNoError(t, wallet.Deposit(Money{int64(BTC), CurrencyBTC}))
+		assertNoError(t, wallet.Withdraw(Money{250, CurrencyUSD}))
+
+		assertMoney(t, wallet.Balance(CurrencyUSD), Money{750, CurrencyUSD})
+		assertMoney(t, wallet.Balance(CurrencyEUR), Money{0, CurrencyEUR})
+
+		got := wallet.Balances()
+		if len(got) != 2 || got[0].Currency != CurrencyBTC || got[1].Currency != CurrencyUSD {
+			t.Errorf("expected BTC then USD balances got %v", got)
+		}
+	})
+
+	t.Run("cannot spend one currency's balance in another", func(t *testing.T) {
+		wallet := &MultiWallet{}
+		wallet.Deposit(Money{1000, CurrencyUSD})
+
+		err := wallet.Withdraw(Money{100, CurrencyEUR})
+		assertError(t, err, ErrInsufficientFunds)
+		assertMoney(t, wallet.Balance(CurrencyUSD), Money{1000, CurrencyUSD})
+	})
+
+	t.Run("says how far short a withdrawal fell", func(t *testing.T) {
+		wallet := &MultiWallet{}
+		wallet.Deposit(Money{1000, CurrencyUSD})
+
+		err := wallet.Withdraw(Money{1250, CurrencyUSD})
+
+		var short *InsufficientMoneyError
+		if !errors.As(err, &short) {
+			t.Fatalf("expected an *InsufficientMoneyError got %v", err)
+		}
+		assertMoney(t, short.Available, Money{1000, CurrencyUSD})
+		assertMoney(t, short.Shortfall(), Money{250, CurrencyUSD})
+	})
+
+	t.Run("validates amounts", func(t *testing.T) {
+		wallet := &MultiWallet{}
+		assertError(t, wallet.Deposit(Money{0, CurrencyUSD}), ErrZeroAmount)
+		assertError(t, wallet.Deposit(Money{-5, CurrencyUSD}), ErrNegativeAmount)
+		assertError(t, wallet.Deposit(Money{5, Currency("XYZ")}), ErrUnknownCurrency)
+	})
+
+	t.Run("exchanges between currencies", func(t *testing.T) {
+		wallet := &MultiWallet{}
+		wallet.Deposit(Money{1000, CurrencyUSD})
+
+		bought, err := wallet.Exchange(Money{400, CurrencyUSD}, CurrencyEUR, rates)
+		assertNoError(t, err)
+		assertMoney(t, bought, Money{360, CurrencyEUR})
+		assertMoney(t, wallet.Balance(CurrencyUSD), Money{600, CurrencyUSD})
+		assertMoney(t, wallet.Balance(CurrencyEUR), Money{360, CurrencyEUR})
+	})
+
+	t.Run("leaves balances alone when an exchange fails", func(t *testing.T) {
+		wallet := &MultiWallet{}
+		wallet.Deposit(Money{1000, CurrencyUSD})
+
+		_, err := wallet.Exchange(Money{2000, CurrencyUSD}, CurrencyEUR, rates)
+		assertError(t, err, ErrInsufficientFunds)
+		_, err = wallet.Exchange(Money{100, CurrencyUSD}, CurrencyJPY, rates)
+		assertError(t, err, ErrNoRate)
+		_, err = wallet.Exchange(Money{1, CurrencyUSD}, CurrencyBTC, btcRates)
+		assertError(t, err, ErrZeroAmount)
+
+		assertMoney(t, wallet.Balance(CurrencyUSD), Money{1000, CurrencyUSD})
+		if got := wallet.Balances(); len(got) != 1 {
+			t.Errorf("expected only a USD balance got %v", got)
+		}
+	})
+}
+
+func assertMoney(t testing.TB, got, want Money) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("expected %s got %s", want, got)
+	}
+}
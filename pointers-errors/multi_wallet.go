@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MultiWallet keeps a separate balance for each currency it has seen. It
+// is safe for concurrent use; the zero value is an empty wallet.
+//
+// It is only a set of balances: unlike Wallet it keeps no ledger, takes
+// no holds and never goes overdrawn.
+type MultiWallet struct {
+	mu       sync.Mutex
+	balances map[Currency]int64
+}
+
+// Balance of one currency; zero if the wallet has never held it.
+func (w *MultiWallet) Balance(c Currency) Money {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return Money{w.balances[c], c}
+}
+
+// Balances lists every currency the wallet holds, ordered by code.
+func (w *MultiWallet) Balances() []Money {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	balances := make([]Money, 0, len(w.balances))
+	for c, minor := range w.balances {
+		balances = append(balances, Money{minor, c})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Currency < balances[j].Currency })
+	return balances
+}
+
+func (w *MultiWallet) Deposit(m Money) error {
+	if err := validateMoney("deposit", m); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.credit(m)
+}
+
+func (w *MultiWallet) Withdraw(m Money) error {
+	if err := validateMoney("withdraw", m); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.debit(m)
+}
+
+// Exchange sells amount for another currency at the provider's rate,
+// returning what was bought. Nothing changes if any step fails.
+func (w *MultiWallet) Exchange(amount Money, to Currency, rates RateProvider) (Money, error) {
+	if err := validateMoney("exchange", amount); err != nil {
+		return Money{}, err
+	}
+	bought, err := Convert(amount, to, rates)
+	if err != nil {
+		return Money{}, err
+	}
+	if bought.Minor == 0 {
+		return Money{}, fmt.Errorf("exchange %s buys less than one minor unit of %s: %w", amount, to, ErrZeroAmount)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.debit(amount); err != nil {
+		return Money{}, err
+	}
+	if err := w.credit(bought); err != nil {
+		w.credit(amount) // put back what was just taken out
+		return Money{}, err
+	}
+	return bought, nil
+}
+
+// credit and debit must be called with w.mu held.
+func (w *MultiWallet) credit(m Money) error {
+	balance, err := Money{w.balances[m.Currency], m.Currency}.Add(m)
+	if err != nil {
+		return err
+	}
+	if w.balances == nil {
+		w.balances = map[Currency]int64{}
+	}
+	w.balances[m.Currency] = balance.Minor
+	return nil
+}
+
+func (w *MultiWallet) debit(m Money) error {
+	available := Money{w.balances[m.Currency], m.Currency}
+	if m.Minor > available.Minor {
+		return &InsufficientMoneyError{Requested: m, Available: available}
+	}
+	w.balances[m.Currency] = available.Minor - m.Minor
+	return nil
+}
+
+func validateMoney(op string, m Money) error {
+	if _, err := m.Currency.Digits(); err != nil {
+		return err
+	}
+	switch {
+	case m.Minor == 0:
+		return fmt.Errorf("%s %s: %w", op, m, ErrZeroAmount)
+	case m.Minor < 0:
+		return fmt.Errorf("%s %s: %w", op, m, ErrNegativeAmount)
+	}
+	return nil
+}
@@ -1,17 +1,10 @@
-package main
+package wallet
 
 import (
 	"errors"
-	"fmt"
 	"testing"
 )
 
-type Bitcoin int
-
-type Wallet struct {
-	balance Bitcoin
-}
-
 type Stringer interface {
 	String() string
 }
@@ -21,49 +14,27 @@ func TestWallet(t *testing.T) {
 	t.Run("deposit", func(t *testing.T) {
 		wallet := Wallet{}
 		wallet.Deposit(Bitcoin(10))
-		assertBalance(t, wallet, Bitcoin(10))
+		assertBalance(t, &wallet, Bitcoin(10))
 	})
 
 	t.Run("withdraw", func(t *testing.T) {
 		wallet := Wallet{balance: Bitcoin(20)}
 		err := wallet.Withdraw(Bitcoin(10))
 		assertNoError(t, err)
-		assertBalance(t, wallet, Bitcoin(10))
+		assertBalance(t, &wallet, Bitcoin(10))
 	})
 
 	t.Run("withdraw insufficient funds", func(t *testing.T) {
 		startingBalance := Bitcoin(20)
-		wallet := Wallet{startingBalance}
+		wallet := Wallet{balance: startingBalance}
 		err := wallet.Withdraw(Bitcoin(100))
 
-		assertBalance(t, wallet, startingBalance)
+		assertBalance(t, &wallet, startingBalance)
 		assertError(t, err, ErrInsufficientFunds)
 	})
 
 }
 
-func (w *Wallet) Deposit(amount Bitcoin) {
-	w.balance += amount
-}
-
-func (w *Wallet) Balance() Bitcoin {
-	return w.balance
-}
-
-var ErrInsufficientFunds = errors.New("cannot withdraw, insufficient funds")
-
-func (w *Wallet) Withdraw(amount Bitcoin) error {
-	if amount > w.balance {
-		return ErrInsufficientFunds
-	}
-	w.balance -= amount
-	return nil
-}
-
-func (b Bitcoin) String() string {
-	return fmt.Sprintf("%d BTC", b)
-}
-
 func assertNoError(t testing.TB, got error) {
 	t.Helper()
 
@@ -72,7 +43,7 @@ func assertNoError(t testing.TB, got error) {
 	}
 }
 
-func assertBalance(t testing.TB, wallet Wallet, want Bitcoin) {
+func assertBalance(t testing.TB, wallet *Wallet, want Bitcoin) {
 	t.Helper()
 
 	got := wallet.Balance()
@@ -87,7 +58,7 @@ func assertError(t testing.TB, got, want error) {
 	if got == nil {
 		t.Fatal("didn't get an error but wanted one")
 	}
-	if got != want {
+	if !errors.Is(got, want) {
 		t.Errorf("expected %q got %q", want, got)
 	}
 }
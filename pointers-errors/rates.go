@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+var ErrNoRate = errors.New("no exchange rate between currencies")
+
+// RateProvider gives the exchange rate from one currency to another: how
+// many whole units of to one whole unit of from buys.
+type RateProvider interface {
+	Rate(from, to Currency) (*big.Rat, error)
+}
+
+// StaticRates is an in-memory RateProvider, for tests and fixed rates. A
+// rate set one way is used inverted for the other.
+type StaticRates struct {
+	mu    sync.RWMutex
+	rates map[[2]Currency]*big.Rat
+}
+
+func NewStaticRates() *StaticRates {
+	return &StaticRates{rates: map[[2]Currency]*big.Rat{}}
+}
+
+// Set records that one unit of from buys rate units of to, e.g.
+// Set(CurrencyBTC, CurrencyUSD, "60000").
+func (s *StaticRates) Set(from, to Currency, rate string) error {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return fmt.Errorf("%w: rate %q", ErrInvalidAmount, rate)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[[2]Currency{from, to}] = r
+	return nil
+}
+
+func (s *StaticRates) Rate(from, to Currency) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.rates[[2]Currency{from, to}]; ok {
+		return new(big.Rat).Set(r), nil
+	}
+	if r, ok := s.rates[[2]Currency{to, from}]; ok {
+		return new(big.Rat).Inv(r), nil
+	}
+	return nil, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+}
+
+// Convert expresses m in another currency at the provider's rate, rounding
+// to the nearest minor unit (halves away from zero).
+func Convert(m Money, to Currency, rates RateProvider) (Money, error) {
+	fromDigits, err := m.Currency.Digits()
+	if err != nil {
+		return Money{}, err
+	}
+	toDigits, err := to.Digits()
+	if err != nil {
+		return Money{}, err
+	}
+	rate, err := rates.Rate(m.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+
+	// minor_to = minor_from * rate * 10^toDigits / 10^fromDigits
+	r := new(big.Rat).SetInt64(m.Minor)
+	r.Mul(r, rate)
+	r.Mul(r, new(big.Rat).SetFrac(pow10(toDigits), pow10(fromDigits)))
+
+	minor := roundHalfAwayFromZero(r)
+	if !minor.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{minor.Int64(), to}, nil
+}
+
+func roundHalfAwayFromZero(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	// (2*num + den) / (2*den) rounds a positive fraction half up
+	num.Mul(num, big.NewInt(2)).Add(num, r.Denom())
+	den := new(big.Int).Mul(r.Denom(), big.NewInt(2))
+	q := num.Quo(num, den)
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q
+}
@@ -0,0 +1,226 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Schedule says when a recurring payment is next due. Next returns the
+// first time strictly after after, in after's location, or the zero time
+// if there is none.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// validator is implemented by the schedules built from plain fields, so
+// Scheduler.Add can refuse out of range ones. ParseCron checks its own.
+type validator interface {
+	validate() error
+}
+
+func checkTime(hour, minute int) error {
+	if hour < 0 || hour > 23 {
+		return fmt.Errorf("%w: hour %d is not 0 to 23", ErrInvalidSchedule, hour)
+	}
+	if minute < 0 || minute > 59 {
+		return fmt.Errorf("%w: minute %d is not 0 to 59", ErrInvalidSchedule, minute)
+	}
+	return nil
+}
+
+// Daily is due every day at Hour:Minute.
+type Daily struct {
+	Hour, Minute int
+}
+
+func (d Daily) validate() error {
+	return checkTime(d.Hour, d.Minute)
+}
+
+func (d Daily) Next(after time.Time) time.Time {
+	y, m, day := after.Date()
+	next := time.Date(y, m, day, d.Hour, d.Minute, 0, 0, after.Location())
+	if !next.After(after) {
+		next = time.Date(y, m, day+1, d.Hour, d.Minute, 0, 0, after.Location())
+	}
+	return next
+}
+
+// Weekly is due every Weekday at Hour:Minute.
+type Weekly struct {
+	Weekday      time.Weekday
+	Hour, Minute int
+}
+
+func (w Weekly) validate() error {
+	if w.Weekday < time.Sunday || w.Weekday > time.Saturday {
+		return fmt.Errorf("%w: weekday %d is not 0 to 6", ErrInvalidSchedule, w.Weekday)
+	}
+	return checkTime(w.Hour, w.Minute)
+}
+
+func (w Weekly) Next(after time.Time) time.Time {
+	y, m, day := after.Date()
+	ahead := (int(w.Weekday) - int(after.Weekday()) + 7) % 7
+	next := time.Date(y, m, day+ahead, w.Hour, w.Minute, 0, 0, after.Location())
+	if !next.After(after) {
+		next = time.Date(y, m, day+ahead+7, w.Hour, w.Minute, 0, 0, after.Location())
+	}
+	return next
+}
+
+// Monthly is due on Day of every month at Hour:Minute. In months shorter
+// than Day it is due on the last day instead, so Day 31 means "month end".
+type Monthly struct {
+	Day, Hour, Minute int
+}
+
+func (mo Monthly) validate() error {
+	if mo.Day < 1 || mo.Day > 31 {
+		return fmt.Errorf("%w: day %d is not 1 to 31", ErrInvalidSchedule, mo.Day)
+	}
+	return checkTime(mo.Hour, mo.Minute)
+}
+
+func (mo Monthly) Next(after time.Time) time.Time {
+	y, m, _ := after.Date()
+	next := mo.in(y, m, after.Location())
+	if !next.After(after) {
+		next = mo.in(y, m+1, after.Location())
+	}
+	return next
+}
+
+func (mo Monthly) in(y int, m time.Month, loc *time.Location) time.Time {
+	// day 0 of the following month is the last day of this one
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, loc).Day()
+	return time.Date(y, m, min(mo.Day, last), mo.Hour, mo.Minute, 0, 0, loc)
+}
+
+// cronSchedule is a parsed five-field cron expression. Each field is a
+// bitset of the values it allows.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// as in cron, when both day fields are restricted either may match
+	domAny, dowAny bool
+}
+
+var cronShortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// ParseCron reads a cron expression: minute, hour, day of month, month and
+// day of week, each a *, a number, a range a-b or a list of them, with an
+// optional /step. Sunday is 0 or 7. The shortcuts @hourly, @daily,
+// @weekly, @monthly and @yearly are also accepted.
+func ParseCron(expr string) (Schedule, error) {
+	if full, ok := cronShortcuts[strings.TrimSpace(expr)]; ok {
+		expr = full
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q: want 5 fields, got %d", ErrInvalidSchedule, expr, len(fields))
+	}
+
+	var c cronSchedule
+	var err error
+	parsers := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 7},
+	}
+	for i, p := range parsers {
+		if *p.field, err = parseCronField(fields[i], p.min, p.max); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSchedule, expr, err)
+		}
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 << 0
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+	return &c, nil
+}
+
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+			rng, step = before, n
+		}
+
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("bad value in %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("bad value in %q", part)
+				}
+			}
+			if from < lo || to > hi || from > to {
+				return 0, fmt.Errorf("%q is outside %d-%d", part, lo, hi)
+			}
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (c *cronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+
+	// every combination repeats within a few years; past that, nothing matches
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<m) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<t.Weekday()) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	// a Thursday
+	after := time.Date(2024, time.January, 4, 10, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		after    time.Time
+		want     time.Time
+	}{
+		{"daily later today", Daily{18, 0}, after, date(2024, 1, 4, 18, 0)},
+		{"daily tomorrow", Daily{9, 0}, after, date(2024, 1, 5, 9, 0)},
+		{"daily not at the same instant", Daily{10, 30}, after, date(2024, 1, 5, 10, 30)},
+		{"weekly later this week", Weekly{time.Saturday, 8, 0}, after, date(2024, 1, 6, 8, 0)},
+		{"weekly next week", Weekly{time.Monday, 8, 0}, after, date(2024, 1, 8, 8, 0)},
+		{"weekly same day, earlier", Weekly{time.Thursday, 9, 0}, after, date(2024, 1, 11, 9, 0)},
+		{"monthly this month", Monthly{15, 0, 0}, after, date(2024, 1, 15, 0, 0)},
+		{"monthly next month", Monthly{1, 0, 0}, after, date(2024, 2, 1, 0, 0)},
+		{"monthly month end in February", Monthly{31, 0, 0}, date(2024, 1, 31, 0, 0), date(2024, 2, 29, 0, 0)},
+		{"monthly back to the 31st", Monthly{31, 0, 0}, date(2024, 2, 29, 0, 0), date(2024, 3, 31, 0, 0)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTime(t, tt.schedule.Next(tt.after), tt.want)
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	after := time.Date(2024, time.January, 4, 10, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", date(2024, 1, 4, 10, 45)},
+		{"0 9 * * 1-5", date(2024, 1, 5, 9, 0)},
+		{"0 0 1,15 * *", date(2024, 1, 15, 0, 0)},
+		{"0 0 * 3 *", date(2024, 3, 1, 0, 0)},
+		{"0 0 * * 7", date(2024, 1, 7, 0, 0)},
+		// with both day fields set, either matching is enough: Friday the 5th
+		{"0 12 13 * 5", date(2024, 1, 5, 12, 0)},
+		{"0 0 29 2 *", date(2024, 2, 29, 0, 0)},
+		{"@monthly", date(2024, 2, 1, 0, 0)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			assertNoError(t, err)
+			assertTime(t, schedule.Next(after), tt.want)
+		})
+	}
+
+	t.Run("never due", func(t *testing.T) {
+		schedule, err := ParseCron("0 0 31 2 *")
+		assertNoError(t, err)
+		if got := schedule.Next(after); !got.IsZero() {
+			t.Errorf("expected no occurrence got %v", got)
+		}
+	})
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		t.Run("rejects "+expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+			assertError(t, err, ErrInvalidSchedule)
+		})
+	}
+}
+
+func date(y int, m time.Month, d, h, min int) time.Time {
+	return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+}
+
+func assertTime(t testing.TB, got, want time.Time) {
+	t.Helper()
+
+	if !got.Equal(want) {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
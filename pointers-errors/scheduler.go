@@ -0,0 +1,287 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRetryDelay    = time.Hour
+	DefaultMaxRetryDelay = 24 * time.Hour
+	DefaultMaxRetries    = 5
+)
+
+var (
+	ErrDuplicatePayment = errors.New("a payment with this name is already scheduled")
+	ErrPaymentNotFound  = errors.New("no payment with this name is scheduled")
+	ErrNoSchedule       = errors.New("payment has no schedule")
+	ErrNoWallet         = errors.New("payment has no wallet to pay from")
+)
+
+// Payer moves the money for scheduled payments. *WalletStore implements
+// it, so every payment is journaled like any other change.
+type Payer interface {
+	Withdraw(id string, amount Bitcoin) error
+	Transfer(from, to string, amount Bitcoin) error
+}
+
+// Payment is a recurring instruction: withdraw Amount from the wallet
+// called From, or transfer it to To when To is set, whenever Schedule is
+// due.
+type Payment struct {
+	Name     string
+	From     string
+	To       string
+	Amount   Bitcoin
+	Schedule Schedule
+}
+
+func (p Payment) pay(wallets Payer) error {
+	if p.To == "" {
+		return wallets.Withdraw(p.From, p.Amount)
+	}
+	return wallets.Transfer(p.From, p.To, p.Amount)
+}
+
+// Result is the outcome of one attempt at a payment. Due is the
+// occurrence being paid; At is when this attempt ran, which is later for
+// retries. Retry is when the next attempt is, or zero if there is none.
+type Result struct {
+	Payment string
+	Due     time.Time
+	At      time.Time
+	Attempt int
+	Err     error
+	Retry   time.Time
+}
+
+type scheduledPayment struct {
+	Payment
+	due     time.Time // the occurrence being paid
+	next    time.Time // when to try it, after due on a retry
+	attempt int
+}
+
+// Scheduler runs recurring payments. It keeps no goroutines of its own:
+// RunDue makes every payment that has fallen due by Now, so a test can
+// move its clock on by months and call RunDue once. Run calls RunDue as
+// time passes.
+//
+// A payment refused for insufficient funds is retried after RetryDelay,
+// doubling each time up to MaxRetryDelay, at most MaxRetries times before
+// that occurrence is given up. Other errors are not retried.
+//
+// Set the fields before adding payments.
+type Scheduler struct {
+	mu       sync.Mutex
+	payments []*scheduledPayment
+	wallets  Payer
+
+	// Now is the scheduler's clock. It defaults to time.Now.
+	Now func() time.Time
+
+	// After is what Run waits on between checks, and should follow the
+	// same clock as Now. It defaults to a real timer.
+	After func(d time.Duration) <-chan time.Time
+
+	// RetryDelay and MaxRetryDelay default to DefaultRetryDelay and
+	// DefaultMaxRetryDelay when zero.
+	RetryDelay, MaxRetryDelay time.Duration
+
+	// MaxRetries defaults to DefaultMaxRetries when zero. A negative value
+	// turns retries off.
+	MaxRetries int
+}
+
+// NewScheduler makes payments from wallets.
+func NewScheduler(wallets Payer) *Scheduler {
+	return &Scheduler{wallets: wallets}
+}
+
+// Add schedules p from its first occurrence after Now. A Daily, Weekly or
+// Monthly schedule with a field out of range is ErrInvalidSchedule.
+func (s *Scheduler) Add(p Payment) error {
+	if err := validateAmount("schedule", p.Amount); err != nil {
+		return err
+	}
+	if p.Schedule == nil {
+		return fmt.Errorf("%w: %q", ErrNoSchedule, p.Name)
+	}
+	if v, ok := p.Schedule.(validator); ok {
+		if err := v.validate(); err != nil {
+			return fmt.Errorf("payment %q: %w", p.Name, err)
+		}
+	}
+	if p.From == "" {
+		return fmt.Errorf("%w: %q", ErrNoWallet, p.Name)
+	}
+	if p.From == p.To {
+		return ErrSameWallet
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(p.Name) >= 0 {
+		return fmt.Errorf("%w: %q", ErrDuplicatePayment, p.Name)
+	}
+	due := p.Schedule.Next(s.now())
+	if due.IsZero() {
+		return fmt.Errorf("%w: %q is never due", ErrInvalidSchedule, p.Name)
+	}
+	s.payments = append(s.payments, &scheduledPayment{Payment: p, due: due, next: due})
+	return nil
+}
+
+func (s *Scheduler) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrPaymentNotFound, name)
+	}
+	s.payments = append(s.payments[:i], s.payments[i+1:]...)
+	return nil
+}
+
+func (s *Scheduler) find(name string) int {
+	for i, p := range s.payments {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Next is when the earliest payment or retry is due; false if none is
+// scheduled.
+func (s *Scheduler) Next() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p := s.earliest(); p != nil {
+		return p.next, true
+	}
+	return time.Time{}, false
+}
+
+func (s *Scheduler) earliest() *scheduledPayment {
+	var first *scheduledPayment
+	for _, p := range s.payments {
+		if first == nil || p.next.Before(first.next) {
+			first = p
+		}
+	}
+	return first
+}
+
+// RunDue makes every payment and retry due at or before Now, oldest
+// first, catching up on any occurrences that were missed, and returns
+// what happened.
+func (s *Scheduler) RunDue() []Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var results []Result
+	for {
+		p := s.earliest()
+		if p == nil || p.next.After(now) {
+			return results
+		}
+		results = append(results, s.attempt(p))
+	}
+}
+
+// attempt makes one try at p at its scheduled time and works out when it
+// is next due. It must be called with s.mu held.
+func (s *Scheduler) attempt(p *scheduledPayment) Result {
+	res := Result{Payment: p.Name, Due: p.due, At: p.next, Attempt: p.attempt + 1}
+	res.Err = p.pay(s.wallets)
+
+	if errors.Is(res.Err, ErrInsufficientFunds) && p.attempt < s.maxRetries() {
+		p.attempt++
+		p.next = p.next.Add(s.backoff(p.attempt))
+		res.Retry = p.next
+		return res
+	}
+
+	p.attempt = 0
+	p.due = p.Schedule.Next(p.due)
+	p.next = p.due
+	if p.due.IsZero() {
+		// the schedule has run out
+		i := s.find(p.Name)
+		s.payments = append(s.payments[:i], s.payments[i+1:]...)
+	}
+	return res
+}
+
+// backoff is the delay before the nth retry.
+func (s *Scheduler) backoff(n int) time.Duration {
+	delay, limit := s.RetryDelay, s.MaxRetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	if limit <= 0 {
+		limit = DefaultMaxRetryDelay
+	}
+	for i := 1; i < n && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+func (s *Scheduler) maxRetries() int {
+	switch {
+	case s.MaxRetries < 0:
+		return 0
+	case s.MaxRetries == 0:
+		return DefaultMaxRetries
+	}
+	return s.MaxRetries
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// Run calls RunDue whenever a payment falls due, passing each result to
+// report, until ctx is done. It checks the clock at least every minute so
+// a clock that jumps is noticed.
+func (s *Scheduler) Run(ctx context.Context, report func(Result)) error {
+	for {
+		for _, res := range s.RunDue() {
+			if report != nil {
+				report(res)
+			}
+		}
+
+		wait := time.Minute
+		if next, ok := s.Next(); ok {
+			wait = min(wait, max(next.Sub(s.now()), 0))
+		}
+		fired, stop := s.after(wait)
+		select {
+		case <-ctx.Done():
+			stop()
+			return ctx.Err()
+		case <-fired:
+		}
+	}
+}
+
+func (s *Scheduler) after(d time.Duration) (<-chan time.Time, func()) {
+	if s.After != nil {
+		return s.After(d), func() {}
+	}
+	timer := time.NewTimer(d)
+	return timer.C, func() { timer.Stop() }
+}
@@ -0,0 +1,264 @@
+package wallet
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newScheduler starts a scheduler and a wallet store on one stub clock,
+// just after midnight on 1 January 2024.
+func newScheduler(t testing.TB) (*Scheduler, *WalletStore, *manualClock) {
+	t.Helper()
+
+	clock := &manualClock{date(2024, 1, 1, 0, 1)}
+	store := openStore(t, journalPath(t))
+	store.Now = clock.Now
+	scheduler := NewScheduler(store)
+	scheduler.Now = clock.Now
+	return scheduler, store, clock
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("pays a monthly subscription for a year in one go", func(t *testing.T) {
+		scheduler, store, clock := newScheduler(t)
+		store.Deposit("team", 100*BTC)
+
+		assertNoError(t, scheduler.Add(Payment{Name: "hosting", From: "team", Amount: 5 * BTC, Schedule: Monthly{1, 9, 0}}))
+		clock.advance(365 * 24 * time.Hour)
+		results := scheduler.RunDue()
+
+		if len(results) != 12 {
+			t.Fatalf("expected 12 payments got %d", len(results))
+		}
+		for i, res := range results {
+			assertNoError(t, res.Err)
+			assertTime(t, res.Due, date(2024, time.Month(i+1), 1, 9, 0))
+		}
+		assertStoreBalance(t, store, "team", 40*BTC)
+
+		next, _ := scheduler.Next()
+		assertTime(t, next, date(2025, 1, 1, 9, 0))
+	})
+
+	t.Run("transfers between wallets", func(t *testing.T) {
+		scheduler, store, clock := newScheduler(t)
+		store.Deposit("team", 10*BTC)
+
+		scheduler.Add(Payment{Name: "support", From: "team", To: "vendor", Amount: 1 * BTC, Schedule: Weekly{time.Friday, 12, 0}})
+		clock.advance(14 * 24 * time.Hour)
+		scheduler.RunDue()
+
+		assertStoreBalance(t, store, "team", 8*BTC)
+		assertStoreBalance(t, store, "vendor", 2*BTC)
+	})
+
+	t.Run("runs payments in time order", func(t *testing.T) {
+		scheduler, store, clock := newScheduler(t)
+		store.Deposit("team", 100*BTC)
+
+		scheduler.Add(Payment{Name: "weekly", From: "team", Amount: 1 * BTC, Schedule: Weekly{time.Monday, 9, 0}})
+		scheduler.Add(Payment{Name: "daily", From: "team", Amount: 1 * BTC, Schedule: Daily{9, 30}})
+		clock.advance(2 * 24 * time.Hour)
+
+		var got []string
+		for _, res := range scheduler.RunDue() {
+			got = append(got, res.Payment)
+		}
+		if want := []string{"weekly", "daily", "daily"}; !slices.Equal(got, want) {
+			t.Errorf("expected %v got %v", want, got)
+		}
+	})
+
+	t.Run("stops paying once removed", func(t *testing.T) {
+		scheduler, store, clock := newScheduler(t)
+		store.Deposit("team", 10*BTC)
+
+		scheduler.Add(Payment{Name: "hosting", From: "team", Amount: 1 * BTC, Schedule: Daily{9, 0}})
+		assertNoError(t, scheduler.Remove("hosting"))
+		clock.advance(48 * time.Hour)
+
+		if results := scheduler.RunDue(); len(results) != 0 {
+			t.Errorf("expected no payments got %v", results)
+		}
+		assertError(t, scheduler.Remove("hosting"), ErrPaymentNotFound)
+	})
+
+	t.Run("rejects bad payments", func(t *testing.T) {
+		scheduler, _, _ := newScheduler(t)
+		never, _ := ParseCron("0 0 30 2 *")
+
+		assertError(t, scheduler.Add(Payment{Name: "a", From: "team", Amount: 0, Schedule: Daily{}}), ErrZeroAmount)
+		assertError(t, scheduler.Add(Payment{Name: "b", From: "team", Amount: 1}), ErrNoSchedule)
+		assertError(t, scheduler.Add(Payment{Name: "c", Amount: 1, Schedule: Daily{}}), ErrNoWallet)
+		assertError(t, scheduler.Add(Payment{Name: "d", From: "team", To: "team", Amount: 1, Schedule: Daily{}}), ErrSameWallet)
+		assertError(t, scheduler.Add(Payment{Name: "e", From: "team", Amount: 1, Schedule: never}), ErrInvalidSchedule)
+		for _, bad := range []Schedule{
+			Daily{24, 0},
+			Daily{9, -1},
+			Weekly{time.Weekday(7), 9, 0},
+			Monthly{0, 9, 0},
+			Monthly{32, 9, 0},
+			Monthly{1, 9, 60},
+		} {
+			assertError(t, scheduler.Add(Payment{Name: "e", From: "team", Amount: 1, Schedule: bad}), ErrInvalidSchedule)
+		}
+
+		assertNoError(t, scheduler.Add(Payment{Name: "f", From: "team", Amount: 1, Schedule: Daily{}}))
+		assertError(t, scheduler.Add(Payment{Name: "f", From: "team", Amount: 1, Schedule: Daily{}}), ErrDuplicatePayment)
+	})
+}
+
+func TestSchedulerRetries(t *testing.T) {
+	t.Run("backs off until funds arrive", func(t *testing.T) {
+		scheduler, store, clock := newScheduler(t)
+
+		scheduler.Add(Payment{Name: "hosting", From: "team", Amount: 5 * BTC, Schedule: Daily{9, 0}})
+		clock.now = date(2024, 1, 1, 17, 0)
+		results := scheduler.RunDue()
+
+		// 9:00, then retries an hour, two and four hours after the last
+		assertAttempts(t, results, date(2024, 1, 1, 9, 0), date(2024, 1, 1, 10, 0), date(2024, 1, 1, 12, 0), date(2024, 1, 1, 16, 0))
+		for _, res := range results {
+			assertError(t, res.Err, ErrInsufficientFunds)
+		}
+		assertTime(t, results[3].Retry, date(2024, 1, 2, 0, 0))
+
+		store.Deposit("team", 5*BTC)
+		clock.now = date(2024, 1, 2, 0, 0)
+		results = scheduler.RunDue()
+
+		assertAttempts(t, results, date(2024, 1, 2, 0, 0))
+		assertNoError(t, results[0].Err)
+		assertTime(t, results[0].Due, date(2024, 1, 1, 9, 0))
+		if results[0].Attempt != 5 {
+			t.Errorf("expected attempt 5 got %d", results[0].Attempt)
+		}
+		assertStoreBalance(t, store, "team", 0)
+
+		next, _ := scheduler.Next()
+		assertTime(t, next, date(2024, 1, 2, 9, 0))
+	})
+
+	t.Run("gives up after MaxRetries and waits for the next occurrence", func(t *testing.T) {
+		scheduler, _, clock := newScheduler(t)
+		scheduler.MaxRetries = 2
+		scheduler.RetryDelay = 10 * time.Minute
+
+		scheduler.Add(Payment{Name: "hosting", From: "team", Amount: 5 * BTC, Schedule: Daily{9, 0}})
+		clock.now = date(2024, 1, 1, 12, 0)
+		results := scheduler.RunDue()
+
+		assertAttempts(t, results, date(2024, 1, 1, 9, 0), date(2024, 1, 1, 9, 10), date(2024, 1, 1, 9, 30))
+		if !results[2].Retry.IsZero() {
+			t.Errorf("expected no further retry got %v", results[2].Retry)
+		}
+		next, _ := scheduler.Next()
+		assertTime(t, next, date(2024, 1, 2, 9, 0))
+	})
+
+	t.Run("caps the delay at MaxRetryDelay", func(t *testing.T) {
+		scheduler := &Scheduler{RetryDelay: time.Hour, MaxRetryDelay: 3 * time.Hour}
+
+		var got []time.Duration
+		for n := 1; n <= 4; n++ {
+			got = append(got, scheduler.backoff(n))
+		}
+		want := []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 3 * time.Hour}
+		if !slices.Equal(got, want) {
+			t.Errorf("expected %v got %v", want, got)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		scheduler, store, clock := newScheduler(t)
+		store.Deposit("team", 1*BTC)
+		store.Deposit("full", maxBitcoin)
+
+		scheduler.Add(Payment{Name: "tip", From: "team", To: "full", Amount: 1 * BTC, Schedule: Daily{9, 0}})
+		clock.now = date(2024, 1, 1, 12, 0)
+		results := scheduler.RunDue()
+
+		assertAttempts(t, results, date(2024, 1, 1, 9, 0))
+		assertError(t, results[0].Err, ErrOverflow)
+	})
+
+	t.Run("retries can be turned off", func(t *testing.T) {
+		scheduler, _, clock := newScheduler(t)
+		scheduler.MaxRetries = -1
+
+		scheduler.Add(Payment{Name: "hosting", From: "team", Amount: 1 * BTC, Schedule: Daily{9, 0}})
+		clock.now = date(2024, 1, 1, 12, 0)
+
+		assertAttempts(t, scheduler.RunDue(), date(2024, 1, 1, 9, 0))
+	})
+}
+
+func TestSchedulerRun(t *testing.T) {
+	scheduler, store, clock := newScheduler(t)
+	store.Deposit("team", 1*BTC)
+	every, _ := ParseCron("* * * * *")
+	scheduler.Add(Payment{Name: "hosting", From: "team", Amount: 1 * BTC, Schedule: every})
+
+	// Run waits on the test instead of a real timer: it asks for a wait on
+	// waits and carries on when the test sends on fire
+	waits, fire := make(chan time.Duration), make(chan time.Time)
+	scheduler.After = func(d time.Duration) <-chan time.Time {
+		waits <- d
+		return fire
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan Result, 1)
+	report := func(res Result) {
+		select {
+		case results <- res:
+		default: // the test only looks at the first
+		}
+	}
+	done := make(chan error, 1)
+	go func() { done <- scheduler.Run(ctx, report) }()
+
+	// nothing is due at 00:01, so Run waits for the payment at 00:02
+	wait := receive(t, waits)
+	if wait != time.Minute {
+		t.Errorf("expected a wait of %v got %v", time.Minute, wait)
+	}
+	clock.advance(wait)
+	fire <- clock.now
+
+	res := receive(t, results)
+	assertNoError(t, res.Err)
+	assertTime(t, res.Due, date(2024, 1, 1, 0, 2))
+	assertStoreBalance(t, store, "team", 0)
+
+	receive(t, waits) // waiting for 00:03
+	cancel()
+	assertError(t, receive(t, done), context.Canceled)
+}
+
+// receive fails the test rather than hang if Run never gets as far as c.
+func receive[T any](t testing.TB, c <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-c:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting on Run")
+	}
+	panic("unreachable")
+}
+
+func assertAttempts(t testing.TB, results []Result, want ...time.Time) {
+	t.Helper()
+
+	if len(results) != len(want) {
+		t.Fatalf("expected %d attempts got %d: %v", len(want), len(results), results)
+	}
+	for i, res := range results {
+		assertTime(t, res.At, want[i])
+	}
+}
@@ -0,0 +1,239 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Wallets is what WalletServer needs from its backing store. *WalletStore
+// implements it.
+type Wallets interface {
+	Exists(id string) bool
+	Balance(id string) Bitcoin
+	Deposit(id string, amount Bitcoin) error
+	Withdraw(id string, amount Bitcoin) error
+}
+
+// WalletResponse is the JSON body returned for a wallet.
+type WalletResponse struct {
+	ID      string  `json:"id"`
+	Balance Bitcoin `json:"balance"`
+}
+
+// AmountRequest is the JSON body accepted by deposit and withdraw.
+type AmountRequest struct {
+	Amount Bitcoin `json:"amount"`
+}
+
+// Problem is an RFC 7807 problem document, the body of every failed
+// request. Requested, Available and Shortfall are only set for
+// insufficient funds.
+type Problem struct {
+	Type      string   `json:"type"`
+	Title     string   `json:"title"`
+	Status    int      `json:"status"`
+	Detail    string   `json:"detail,omitempty"`
+	Instance  string   `json:"instance,omitempty"`
+	Requested *Bitcoin `json:"requested,omitempty"`
+	Available *Bitcoin `json:"available,omitempty"`
+	Shortfall *Bitcoin `json:"shortfall,omitempty"`
+}
+
+const problemContentType = "application/problem+json"
+
+// WalletServer serves Wallets over HTTP the way PlayerServer in app serves
+// scores.
+//
+// Deposits and withdrawals may carry an Idempotency-Key header. The first
+// response for a key is kept for IdempotencyTTL and replayed, with an
+// Idempotent-Replayed header, for any retry of the same request, so a
+// retried withdrawal is applied once. Reusing a key for a different
+// request is a 422; retrying while the first attempt is still running is
+// a 409. Server errors are kept and replayed like any other response,
+// since the change may have been applied; retry with a new key once the
+// balance has been checked. Keys are held in memory only and are
+// forgotten when the server restarts.
+type WalletServer struct {
+	store       Wallets
+	idempotency *idempotencyCache
+	http.Handler
+
+	// Now times out idempotency keys. It defaults to time.Now.
+	Now func() time.Time
+
+	// ErrorLog records the cause of each 500, which clients are not shown.
+	// It defaults to the log package's standard logger.
+	ErrorLog *log.Logger
+}
+
+// IdempotencyTTL is how long a response is kept for its Idempotency-Key.
+const IdempotencyTTL = 24 * time.Hour
+
+func NewWalletServer(store Wallets) *WalletServer {
+	s := new(WalletServer)
+	s.store = store
+	s.idempotency = newIdempotencyCache()
+
+	router := http.NewServeMux()
+	router.Handle("GET /wallets/{id}", http.HandlerFunc(s.balanceHandler))
+	router.Handle("POST /wallets/{id}/deposit", http.HandlerFunc(s.depositHandler))
+	router.Handle("POST /wallets/{id}/withdraw", http.HandlerFunc(s.withdrawHandler))
+
+	s.Handler = router
+	return s
+}
+
+func (s *WalletServer) balanceHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.store.Exists(id) {
+		writeResponse(w, problemResponse(r, http.StatusNotFound, "Wallet not found", fmt.Sprintf("no wallet called %q", id)))
+		return
+	}
+	writeResponse(w, jsonResponse(http.StatusOK, WalletResponse{id, s.store.Balance(id)}))
+}
+
+func (s *WalletServer) depositHandler(w http.ResponseWriter, r *http.Request) {
+	s.change(w, r, s.store.Deposit)
+}
+
+func (s *WalletServer) withdrawHandler(w http.ResponseWriter, r *http.Request) {
+	s.change(w, r, s.store.Withdraw)
+}
+
+// change applies a deposit or withdrawal, honouring Idempotency-Key.
+func (s *WalletServer) change(w http.ResponseWriter, r *http.Request, apply func(string, Bitcoin) error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeResponse(w, problemResponse(r, http.StatusBadRequest, "Invalid request body", err.Error()))
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		writeResponse(w, s.applyChange(r, body, apply))
+		return
+	}
+
+	fingerprint := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "\n" + string(body)))
+	cached, err := s.idempotency.begin(key, fingerprint, s.now())
+	switch {
+	case errors.Is(err, errKeyInUse):
+		writeResponse(w, problemResponse(r, http.StatusConflict, "Request in progress", err.Error()))
+		return
+	case errors.Is(err, errKeyReused):
+		writeResponse(w, problemResponse(r, http.StatusUnprocessableEntity, "Idempotency-Key reused", err.Error()))
+		return
+	case cached != nil:
+		w.Header().Set("Idempotent-Replayed", "true")
+		writeResponse(w, *cached)
+		return
+	}
+
+	finished := false
+	defer func() {
+		if !finished {
+			// apply panicked; free the key rather than refuse every retry
+			s.idempotency.abandon(key)
+		}
+	}()
+	res := s.applyChange(r, body, apply)
+	s.idempotency.finish(key, res, s.now().Add(IdempotencyTTL))
+	finished = true
+	writeResponse(w, res)
+}
+
+func (s *WalletServer) applyChange(r *http.Request, body []byte, apply func(string, Bitcoin) error) response {
+	id := r.PathValue("id")
+
+	var req AmountRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return problemResponse(r, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+	if err := apply(id, req.Amount); err != nil {
+		return s.errorResponse(r, err)
+	}
+	return jsonResponse(http.StatusOK, WalletResponse{id, s.store.Balance(id)})
+}
+
+func (s *WalletServer) logf(format string, args ...any) {
+	if s.ErrorLog == nil {
+		log.Printf(format, args...)
+		return
+	}
+	s.ErrorLog.Printf(format, args...)
+}
+
+func (s *WalletServer) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// errorResponse maps the wallet's errors onto problem documents. Anything
+// unexpected is a 500 whose detail says nothing of the cause, which may
+// name files or other internals; the cause goes to ErrorLog instead.
+func (s *WalletServer) errorResponse(r *http.Request, err error) response {
+	var funds *InsufficientFundsError
+	switch {
+	case errors.As(err, &funds):
+		requested, available, shortfall := funds.Requested, funds.Available, funds.Shortfall()
+		return encodeResponse(http.StatusConflict, problemContentType, Problem{
+			Type:      "/problems/insufficient-funds",
+			Title:     "Insufficient funds",
+			Status:    http.StatusConflict,
+			Detail:    err.Error(),
+			Instance:  r.URL.Path,
+			Requested: &requested,
+			Available: &available,
+			Shortfall: &shortfall,
+		})
+	case errors.Is(err, ErrInsufficientFunds):
+		return problemResponse(r, http.StatusConflict, "Insufficient funds", err.Error())
+	case errors.Is(err, ErrZeroAmount), errors.Is(err, ErrNegativeAmount), errors.Is(err, ErrOverflow):
+		return problemResponse(r, http.StatusUnprocessableEntity, "Invalid amount", err.Error())
+	}
+	s.logf("%s %s: %v", r.Method, r.URL.Path, err)
+	return problemResponse(r, http.StatusInternalServerError, "Internal server error", "the change may or may not have been applied; check the balance before retrying")
+}
+
+// response is a rendered reply, kept whole so it can be replayed.
+type response struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+func jsonResponse(status int, body any) response {
+	return encodeResponse(status, "application/json", body)
+}
+
+func problemResponse(r *http.Request, status int, title, detail string) response {
+	return encodeResponse(status, problemContentType, Problem{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
+
+func encodeResponse(status int, contentType string, body any) response {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return response{http.StatusInternalServerError, "text/plain; charset=utf-8", []byte(err.Error())}
+	}
+	return response{status, contentType, append(data, '\n')}
+}
+
+func writeResponse(w http.ResponseWriter, res response) {
+	w.Header().Set("Content-Type", res.contentType)
+	w.WriteHeader(res.status)
+	w.Write(res.body)
+}
@@ -0,0 +1,352 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGETWallet(t *testing.T) {
+	store := openStore(t, journalPath(t))
+	store.Deposit("alice", 2*BTC)
+	server := NewWalletServer(store)
+
+	t.Run("returns the balance", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/wallets/alice", nil))
+
+		assertStatus(t, res, http.StatusOK)
+		assertContentType(t, res, "application/json")
+		assertJSON(t, res, WalletResponse{"alice", 2 * BTC})
+	})
+
+	t.Run("returns 404 for an unknown wallet", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/wallets/nobody", nil))
+
+		assertStatus(t, res, http.StatusNotFound)
+		assertContentType(t, res, problemContentType)
+	})
+}
+
+func TestPOSTDeposit(t *testing.T) {
+	store := openStore(t, journalPath(t))
+	server := NewWalletServer(store)
+
+	t.Run("deposits the amount", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newAmountRequest("alice", "deposit", `{"amount": "1.5 BTC"}`))
+
+		assertStatus(t, res, http.StatusOK)
+		assertJSON(t, res, WalletResponse{"alice", 150_000_000})
+		assertStoreBalance(t, store, "alice", 150_000_000)
+	})
+
+	t.Run("returns 400 for a malformed body", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newAmountRequest("alice", "deposit", `{"amount": "lots"}`))
+
+		assertStatus(t, res, http.StatusBadRequest)
+		assertContentType(t, res, problemContentType)
+	})
+
+	t.Run("returns 422 for a zero amount", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newAmountRequest("alice", "deposit", `{"amount": 0}`))
+
+		assertStatus(t, res, http.StatusUnprocessableEntity)
+		assertContentType(t, res, problemContentType)
+	})
+}
+
+func TestPOSTWithdraw(t *testing.T) {
+	store := openStore(t, journalPath(t))
+	store.Deposit("alice", 5*BTC)
+	server := NewWalletServer(store)
+
+	t.Run("withdraws the amount", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newAmountRequest("alice", "withdraw", `{"amount": 2}`))
+
+		assertStatus(t, res, http.StatusOK)
+		assertJSON(t, res, WalletResponse{"alice", 3 * BTC})
+	})
+
+	t.Run("returns 409 with a problem document for insufficient funds", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newAmountRequest("alice", "withdraw", `{"amount": 10}`))
+
+		assertStatus(t, res, http.StatusConflict)
+		assertContentType(t, res, problemContentType)
+
+		requested, available, shortfall := 10*BTC, 3*BTC, 7*BTC
+		assertJSON(t, res, Problem{
+			Type:      "/problems/insufficient-funds",
+			Title:     "Insufficient funds",
+			Status:    http.StatusConflict,
+			Detail:    "cannot withdraw, insufficient funds: requested 10 BTC, available 3 BTC, short by 7 BTC",
+			Instance:  "/wallets/alice/withdraw",
+			Requested: &requested,
+			Available: &available,
+			Shortfall: &shortfall,
+		})
+		assertStoreBalance(t, store, "alice", 3*BTC)
+	})
+
+	t.Run("returns 500 when the store fails, logging rather than showing why", func(t *testing.T) {
+		server := NewWalletServer(&StubWallets{err: errors.New("write /var/lib/wallets.journal: disk full")})
+		var logged strings.Builder
+		server.ErrorLog = log.New(&logged, "", 0)
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newAmountRequest("alice", "withdraw", `{"amount": 1}`))
+
+		assertStatus(t, res, http.StatusInternalServerError)
+		assertContentType(t, res, problemContentType)
+		if strings.Contains(res.Body.String(), "wallets.journal") {
+			t.Errorf("expected the cause kept from the client got %s", res.Body)
+		}
+		if !strings.Contains(logged.String(), "disk full") {
+			t.Errorf("expected the cause logged got %q", logged.String())
+		}
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Run("applies a retried withdrawal once", func(t *testing.T) {
+		store := openStore(t, journalPath(t))
+		store.Deposit("alice", 5*BTC)
+		server := NewWalletServer(store)
+
+		first := httptest.NewRecorder()
+		server.ServeHTTP(first, newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+		retry := httptest.NewRecorder()
+		server.ServeHTTP(retry, newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+
+		assertStatus(t, retry, http.StatusOK)
+		if retry.Body.String() != first.Body.String() {
+			t.Errorf("expected the retry to replay %q got %q", first.Body, retry.Body)
+		}
+		if got := retry.Header().Get("Idempotent-Replayed"); got != "true" {
+			t.Errorf("expected Idempotent-Replayed: true got %q", got)
+		}
+		assertStoreBalance(t, store, "alice", 3*BTC)
+	})
+
+	t.Run("replays a refused withdrawal too", func(t *testing.T) {
+		store := openStore(t, journalPath(t))
+		server := NewWalletServer(store)
+
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+		store.Deposit("alice", 5*BTC)
+		retry := httptest.NewRecorder()
+		server.ServeHTTP(retry, newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+
+		assertStatus(t, retry, http.StatusConflict)
+		assertStoreBalance(t, store, "alice", 5*BTC)
+	})
+
+	t.Run("returns 422 when a key is reused for a different request", func(t *testing.T) {
+		store := openStore(t, journalPath(t))
+		store.Deposit("alice", 5*BTC)
+		server := NewWalletServer(store)
+
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newKeyedRequest("alice", "withdraw", `{"amount": 3}`, "key-1"))
+
+		assertStatus(t, res, http.StatusUnprocessableEntity)
+		assertStoreBalance(t, store, "alice", 3*BTC)
+	})
+
+	t.Run("returns 409 while the first attempt is running", func(t *testing.T) {
+		stub := &StubWallets{block: make(chan struct{}), called: make(chan struct{}, 1)}
+		server := NewWalletServer(stub)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+		}()
+		<-stub.called
+
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+		close(stub.block)
+		wg.Wait()
+
+		assertStatus(t, res, http.StatusConflict)
+		if got := stub.callCount(); got != 1 {
+			t.Errorf("expected 1 call got %d", got)
+		}
+	})
+
+	t.Run("replays a server error, which may have applied the change", func(t *testing.T) {
+		stub := &StubWallets{err: ErrJournalFailed}
+		server := NewWalletServer(stub)
+
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+		stub.setErr(nil)
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+
+		assertStatus(t, res, http.StatusInternalServerError)
+		if got := res.Header().Get("Idempotent-Replayed"); got != "true" {
+			t.Errorf("expected Idempotent-Replayed: true got %q", got)
+		}
+		if got := stub.callCount(); got != 1 {
+			t.Errorf("expected 1 call got %d", got)
+		}
+	})
+
+	t.Run("frees the key when the store panics", func(t *testing.T) {
+		stub := &StubWallets{panics: true}
+		server := NewWalletServer(stub)
+
+		func() {
+			defer func() { recover() }()
+			server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+		}()
+		stub.mu.Lock()
+		stub.panics = false
+		stub.mu.Unlock()
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+
+		assertStatus(t, res, http.StatusOK)
+		if got := stub.callCount(); got != 2 {
+			t.Errorf("expected 2 calls got %d", got)
+		}
+	})
+
+	t.Run("forgets keys after IdempotencyTTL", func(t *testing.T) {
+		store := openStore(t, journalPath(t))
+		store.Deposit("alice", 5*BTC)
+		server := NewWalletServer(store)
+		clock := &manualClock{ledgerStart}
+		server.Now = clock.Now
+
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+		clock.advance(IdempotencyTTL + time.Second)
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "withdraw", `{"amount": 2}`, "key-1"))
+
+		assertStoreBalance(t, store, "alice", 1*BTC)
+	})
+
+	t.Run("clears out expired keys", func(t *testing.T) {
+		server := NewWalletServer(&StubWallets{})
+		clock := &manualClock{ledgerStart}
+		server.Now = clock.Now
+
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-1"))
+		clock.advance(IdempotencyTTL + time.Second)
+		server.ServeHTTP(httptest.NewRecorder(), newKeyedRequest("alice", "deposit", `{"amount": 1}`, "key-2"))
+
+		if got := len(server.idempotency.entries); got != 1 {
+			t.Errorf("expected 1 key kept got %d", got)
+		}
+	})
+}
+
+// StubWallets counts changes, optionally failing them or panicking,
+// signalling called and blocking until block is closed.
+type StubWallets struct {
+	mu     sync.Mutex
+	calls  int
+	err    error
+	panics bool
+	called chan struct{}
+	block  chan struct{}
+}
+
+func (s *StubWallets) Exists(id string) bool {
+	return true
+}
+
+func (s *StubWallets) Balance(id string) Bitcoin {
+	return 0
+}
+
+func (s *StubWallets) Deposit(id string, amount Bitcoin) error {
+	return s.change()
+}
+
+func (s *StubWallets) Withdraw(id string, amount Bitcoin) error {
+	return s.change()
+}
+
+func (s *StubWallets) change() error {
+	s.mu.Lock()
+	s.calls++
+	err, panics := s.err, s.panics
+	s.mu.Unlock()
+
+	if panics {
+		panic("stub wallets panicked")
+	}
+	if s.called != nil {
+		s.called <- struct{}{}
+	}
+	if s.block != nil {
+		<-s.block
+	}
+	return err
+}
+
+func (s *StubWallets) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func (s *StubWallets) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func newAmountRequest(id, action, body string) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/wallets/"+id+"/"+action, strings.NewReader(body))
+}
+
+func newKeyedRequest(id, action, body, key string) *http.Request {
+	req := newAmountRequest(id, action, body)
+	req.Header.Set("Idempotency-Key", key)
+	return req
+}
+
+func assertStatus(t testing.TB, res *httptest.ResponseRecorder, want int) {
+	t.Helper()
+
+	if res.Code != want {
+		t.Errorf("expected status %d got %d (%s)", want, res.Code, res.Body)
+	}
+}
+
+func assertContentType(t testing.TB, res *httptest.ResponseRecorder, want string) {
+	t.Helper()
+
+	if got := res.Header().Get("Content-Type"); got != want {
+		t.Errorf("expected Content-Type %q got %q", want, got)
+	}
+}
+
+func assertJSON[T any](t testing.TB, res *httptest.ResponseRecorder, want T) {
+	t.Helper()
+
+	var got T
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatalf("could not decode %T: %v", got, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v got %+v", want, got)
+	}
+}
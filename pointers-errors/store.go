@@ -0,0 +1,349 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCorruptJournal = errors.New("wallet journal is corrupt")
+	ErrJournalFailed  = errors.New("wallet journal write failed; reopen the store")
+	ErrStoreClosed    = errors.New("wallet store is closed")
+)
+
+// CorruptTailError describes a partly written record found at the end of
+// a journal, left there by a crash before the write was synced. Opening
+// the store cuts it off; CorruptTail reports what was cut.
+type CorruptTailError struct {
+	Offset  int64 // where the bad record started
+	Dropped int64 // bytes removed from the end of the file
+	Reason  string
+}
+
+func (e *CorruptTailError) Error() string {
+	return fmt.Sprintf("%v: dropped %d bytes at offset %d: %s", ErrCorruptJournal, e.Dropped, e.Offset, e.Reason)
+}
+
+func (e *CorruptTailError) Unwrap() error {
+	return ErrCorruptJournal
+}
+
+// journalFile is the part of *os.File a WalletStore writes through, so
+// tests can stand in a file that crashes.
+type journalFile interface {
+	io.Writer
+	Sync() error
+	Close() error
+}
+
+// Each journal record is framed as a big-endian payload length, the CRC-32C
+// of the payload, the CRC-32C of those first eight bytes, then the payload
+// itself: a JSON journalRecord. The header has its own checksum so that a
+// damaged length is caught before it is trusted to find the record's end.
+const frameHeader = 12
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// journalRecord holds every entry of one operation, so a transfer's two
+// sides are written, and lost, together.
+type journalRecord struct {
+	Entries []journalEntry `json:"entries"`
+}
+
+type journalEntry struct {
+	Wallet string `json:"wallet"`
+	Entry
+}
+
+// WalletStore keeps named wallets and writes every change to a
+// write-ahead journal, fsyncing it before the change is applied and the
+// call returns. Opening the store replays the journal.
+//
+// If a journal write or sync fails the store stops accepting changes and
+// returns ErrJournalFailed, since the file's tail is then unknown. The
+// failed change was never acknowledged; after reopening it may or may not
+// be there, depending on how much reached the disk.
+type WalletStore struct {
+	mu      sync.Mutex
+	file    journalFile
+	wallets map[string]*Wallet
+	tail    *CorruptTailError
+	err     error
+
+	// Now stamps new ledger entries. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// OpenWalletStore opens or creates the journal at path and replays it. A
+// torn record at the end is cut off (see CorruptTail); damage anywhere
+// else is ErrCorruptJournal.
+func OpenWalletStore(path string) (*WalletStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &WalletStore{file: f, wallets: map[string]*Wallet{}}
+	if err := s.replay(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// replay applies every whole record in f, truncating a torn tail.
+func (s *WalletStore) replay(f *os.File) error {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	for offset < int64(len(data)) {
+		payload, reason, tail := readFrame(data[offset:])
+		if reason != "" {
+			if !tail {
+				return fmt.Errorf("%w: record at offset %d: %s", ErrCorruptJournal, offset, reason)
+			}
+			s.tail = &CorruptTailError{offset, int64(len(data)) - offset, reason}
+			if err := f.Truncate(offset); err != nil {
+				return err
+			}
+			if err := f.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+
+		var record journalRecord
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return fmt.Errorf("%w: record at offset %d: %v", ErrCorruptJournal, offset, err)
+		}
+		for _, e := range record.Entries {
+			s.wallet(e.Wallet).apply(e.Entry)
+		}
+		offset += frameHeader + int64(len(payload))
+	}
+
+	for id, w := range s.wallets {
+		if _, err := Replay(w.ledger); err != nil {
+			return fmt.Errorf("%w: wallet %q: %w", ErrCorruptJournal, id, err)
+		}
+	}
+	return nil
+}
+
+// readFrame returns the payload of the record at the start of data. If the
+// record is bad it returns why, and whether it is the last thing in data
+// and so a torn write rather than damage in the middle of the journal.
+// Only a record that runs to the end of data can be torn; a header that
+// fails its checksum never is, since its length cannot be believed.
+func readFrame(data []byte) (payload []byte, reason string, tail bool) {
+	if len(data) < frameHeader {
+		return nil, "incomplete header", true
+	}
+	if crc32.Checksum(data[:8], crcTable) != binary.BigEndian.Uint32(data[8:]) {
+		return nil, "header checksum mismatch", false
+	}
+	size := int64(binary.BigEndian.Uint32(data))
+	if frameHeader+size > int64(len(data)) {
+		return nil, "incomplete record", true
+	}
+	payload = data[frameHeader : frameHeader+size]
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(data[4:]) {
+		return nil, "checksum mismatch", frameHeader+size == int64(len(data))
+	}
+	return payload, "", false
+}
+
+// CorruptTail reports the torn record cut off when the store was opened,
+// or nil if the journal ended cleanly.
+func (s *WalletStore) CorruptTail() *CorruptTailError {
+	return s.tail
+}
+
+// wallet returns the wallet called id, creating it empty. It must be
+// called with s.mu held, or during replay.
+func (s *WalletStore) wallet(id string) *Wallet {
+	w, ok := s.wallets[id]
+	if !ok {
+		w = &Wallet{Now: s.now}
+		s.wallets[id] = w
+	}
+	return w
+}
+
+func (s *WalletStore) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// IDs lists, sorted, the wallets that have had at least one change.
+func (s *WalletStore) IDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.wallets))
+	for id, w := range s.wallets {
+		if len(w.Ledger()) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Exists reports whether the wallet called id has had at least one change.
+func (s *WalletStore) Exists(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.wallets[id]
+	return ok && len(w.Ledger()) > 0
+}
+
+// Balance of the wallet called id; zero if it has never been used.
+func (s *WalletStore) Balance(id string) Bitcoin {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.wallets[id]; ok {
+		return w.Balance()
+	}
+	return 0
+}
+
+func (s *WalletStore) Ledger(id string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.wallets[id]; ok {
+		return w.Ledger()
+	}
+	return nil
+}
+
+func (s *WalletStore) Deposit(id string, amount Bitcoin) error {
+	if err := validateAmount("deposit", amount); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.wallet(id)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	balance, err := w.balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	return s.commit(journalEntry{id, w.entry(EntryDeposit, amount, balance)})
+}
+
+// Withdraw returns only once the withdrawal is synced to the journal.
+func (s *WalletStore) Withdraw(id string, amount Bitcoin) error {
+	if err := validateAmount("withdraw", amount); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.wallet(id)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	balance, err := w.debit(amount)
+	if err != nil {
+		return err
+	}
+	return s.commit(journalEntry{id, w.entry(EntryWithdrawal, amount, balance)})
+}
+
+// Transfer moves amount between two wallets in the store, journaling both
+// sides as one record.
+func (s *WalletStore) Transfer(from, to string, amount Bitcoin) error {
+	if err := validateAmount("transfer", amount); err != nil {
+		return err
+	}
+	if from == to {
+		return ErrSameWallet
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// s.mu already keeps other changes out, so lock order does not matter
+	src, dst := s.wallet(from), s.wallet(to)
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	debited, err := src.debit(amount)
+	if err != nil {
+		return err
+	}
+	credited, err := dst.balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	return s.commit(
+		journalEntry{from, src.entry(EntryTransferOut, amount, debited)},
+		journalEntry{to, dst.entry(EntryTransferIn, amount, credited)},
+	)
+}
+
+// commit writes and syncs one record, then applies its entries. It must be
+// called with s.mu and the wallets' locks held.
+func (s *WalletStore) commit(entries ...journalEntry) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	payload, err := json.Marshal(journalRecord{entries})
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, frameHeader, frameHeader+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.Checksum(payload, crcTable))
+	binary.BigEndian.PutUint32(frame[8:], crc32.Checksum(frame[:8], crcTable))
+	frame = append(frame, payload...)
+
+	if _, err := s.file.Write(frame); err != nil {
+		s.err = fmt.Errorf("%w: %w", ErrJournalFailed, err)
+		return s.err
+	}
+	if err := s.file.Sync(); err != nil {
+		s.err = fmt.Errorf("%w: %w", ErrJournalFailed, err)
+		return s.err
+	}
+
+	for _, e := range entries {
+		s.wallets[e.Wallet].apply(e.Entry)
+	}
+	return nil
+}
+
+func (s *WalletStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err == nil {
+		s.err = ErrStoreClosed
+	}
+	return s.file.Close()
+}
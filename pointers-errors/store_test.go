@@ -0,0 +1,199 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWalletStore(t *testing.T) {
+	t.Run("replays the journal when reopened", func(t *testing.T) {
+		path := journalPath(t)
+		store := openStore(t, path)
+		clock := &manualClock{ledgerStart}
+		store.Now = clock.Now
+
+		assertNoError(t, store.Deposit("alice", 10*BTC))
+		clock.advance(time.Minute)
+		assertNoError(t, store.Withdraw("alice", 3*BTC))
+		assertNoError(t, store.Transfer("alice", "bob", 2*BTC))
+		want := store.Ledger("alice")
+		store.Close()
+
+		store = openStore(t, path)
+		assertStoreBalance(t, store, "alice", 5*BTC)
+		assertStoreBalance(t, store, "bob", 2*BTC)
+		assertLedger(t, store.Ledger("alice"), want)
+		if got := store.IDs(); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+			t.Errorf("expected [alice bob] got %v", got)
+		}
+		if store.CorruptTail() != nil {
+			t.Errorf("expected a clean journal got %v", store.CorruptTail())
+		}
+	})
+
+	t.Run("writes nothing for a refused change", func(t *testing.T) {
+		path := journalPath(t)
+		store := openStore(t, path)
+		store.Deposit("alice", 1*BTC)
+		size := fileSize(t, path)
+
+		assertError(t, store.Withdraw("alice", 5*BTC), ErrInsufficientFunds)
+		assertError(t, store.Transfer("alice", "bob", 5*BTC), ErrInsufficientFunds)
+		assertError(t, store.Deposit("alice", 0), ErrZeroAmount)
+
+		if got := fileSize(t, path); got != size {
+			t.Errorf("expected the journal to stay %d bytes got %d", size, got)
+		}
+		if got := store.IDs(); !reflect.DeepEqual(got, []string{"alice"}) {
+			t.Errorf("expected [alice] got %v", got)
+		}
+	})
+
+	t.Run("refuses changes once closed", func(t *testing.T) {
+		store := openStore(t, journalPath(t))
+		store.Close()
+
+		assertError(t, store.Deposit("alice", 1*BTC), ErrStoreClosed)
+	})
+
+	t.Run("refuses a journal damaged before its end", func(t *testing.T) {
+		path := journalPath(t)
+		store := openStore(t, path)
+		store.Deposit("alice", 1*BTC)
+		store.Deposit("alice", 2*BTC)
+		store.Close()
+
+		data, _ := os.ReadFile(path)
+		data[frameHeader+2] ^= 0xff
+		os.WriteFile(path, data, 0o600)
+
+		_, err := OpenWalletStore(path)
+		assertError(t, err, ErrCorruptJournal)
+	})
+
+	t.Run("refuses a damaged length rather than cutting the journal there", func(t *testing.T) {
+		path := journalPath(t)
+		store := openStore(t, path)
+		store.Deposit("alice", 1*BTC)
+		store.Deposit("alice", 2*BTC)
+		store.Deposit("alice", 3*BTC)
+		store.Close()
+
+		data, _ := os.ReadFile(path)
+		second := frameHeader + int(binary.BigEndian.Uint32(data))
+		data[second] ^= 0x01 // now claims to run far past the end of the file
+		os.WriteFile(path, data, 0o600)
+
+		_, err := OpenWalletStore(path)
+		assertError(t, err, ErrCorruptJournal)
+		if got := fileSize(t, path); got != int64(len(data)) {
+			t.Errorf("expected the journal left at %d bytes got %d", len(data), got)
+		}
+	})
+}
+
+func TestWalletStoreCrash(t *testing.T) {
+	// each case crashes during a Withdraw after the record was handed to
+	// the OS but before Sync, with only kept bytes of it reaching the disk
+	cases := []struct {
+		name        string
+		kept        func(frame int) int
+		wantBalance Bitcoin
+		wantTail    bool
+	}{
+		{"nothing reached the disk", func(int) int { return 0 }, 10 * BTC, false},
+		{"part of the header reached the disk", func(int) int { return 3 }, 10 * BTC, true},
+		{"part of the record reached the disk", func(n int) int { return n - 5 }, 10 * BTC, true},
+		{"all of the record reached the disk", func(n int) int { return n }, 6 * BTC, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			path := journalPath(t)
+			store := openStore(t, path)
+			assertNoError(t, store.Deposit("alice", 10*BTC))
+
+			store.file = &crashingFile{File: store.file.(*os.File), kept: tt.kept}
+			err := store.Withdraw("alice", 4*BTC)
+
+			assertError(t, err, ErrJournalFailed)
+			assertStoreBalance(t, store, "alice", 10*BTC)
+			assertError(t, store.Deposit("alice", 1*BTC), ErrJournalFailed)
+
+			store = openStore(t, path)
+			assertStoreBalance(t, store, "alice", tt.wantBalance)
+			if tail := store.CorruptTail(); (tail != nil) != tt.wantTail {
+				t.Fatalf("expected a corrupt tail: %v got %v", tt.wantTail, tail)
+			}
+
+			// the store carries on after the repair, and the repair is durable
+			assertNoError(t, store.Deposit("alice", 1*BTC))
+			store.Close()
+			store = openStore(t, path)
+			assertStoreBalance(t, store, "alice", tt.wantBalance+1*BTC)
+			if tail := store.CorruptTail(); tail != nil {
+				t.Errorf("expected a clean journal after the repair got %v", tail)
+			}
+		})
+	}
+}
+
+var errCrash = errors.New("simulated crash")
+
+// crashingFile stands in for the journal during a crash: of each write,
+// only kept(len) bytes reach the file, and Sync never completes.
+type crashingFile struct {
+	*os.File
+	kept func(frame int) int
+}
+
+func (f *crashingFile) Write(p []byte) (int, error) {
+	if _, err := f.File.Write(p[:f.kept(len(p))]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (f *crashingFile) Sync() error {
+	f.File.Close()
+	return errCrash
+}
+
+func journalPath(t testing.TB) string {
+	t.Helper()
+	return filepath.Join(t.TempDir(), "wallets.journal")
+}
+
+func openStore(t testing.TB, path string) *WalletStore {
+	t.Helper()
+
+	store, err := OpenWalletStore(path)
+	if err != nil {
+		t.Fatalf("could not open the store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func fileSize(t testing.TB, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func assertStoreBalance(t testing.TB, store *WalletStore, id string, want Bitcoin) {
+	t.Helper()
+
+	if got := store.Balance(id); got != want {
+		t.Errorf("expected %s got %s", want, got)
+	}
+}
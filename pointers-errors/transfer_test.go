@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestTransfer(t *testing.T) {
+	t.Run("moves funds between wallets", func(t *testing.T) {
+		from := &Wallet{balance: Bitcoin(20)}
+		to := &Wallet{balance: Bitcoin(5)}
+
+		err := Transfer(from, to, Bitcoin(15))
+
+		assertNoError(t, err)
+		assertBalance(t, from, Bitcoin(5))
+		assertBalance(t, to, Bitcoin(20))
+	})
+
+	t.Run("leaves both wallets alone on insufficient funds", func(t *testing.T) {
+		from := &Wallet{balance: Bitcoin(20)}
+		to := &Wallet{balance: Bitcoin(5)}
+
+		err := Transfer(from, to, Bitcoin(100))
+
+		assertError(t, err, ErrInsufficientFunds)
+		assertBalance(t, from, Bitcoin(20))
+		assertBalance(t, to, Bitcoin(5))
+	})
+
+	t.Run("refuses to transfer to the same wallet", func(t *testing.T) {
+		wallet := &Wallet{balance: Bitcoin(20)}
+
+		err := Transfer(wallet, wallet, Bitcoin(10))
+
+		assertError(t, err, ErrSameWallet)
+		assertBalance(t, wallet, Bitcoin(20))
+	})
+}
+
+func TestWalletConcurrently(t *testing.T) {
+	t.Run("deposits and withdrawals are not lost", func(t *testing.T) {
+		wallet := &Wallet{balance: Bitcoin(1000)}
+		const workers = 1000
+
+		var wg sync.WaitGroup
+		wg.Add(2 * workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				wallet.Deposit(Bitcoin(2))
+			}()
+			go func() {
+				defer wg.Done()
+				wallet.Withdraw(Bitcoin(1))
+			}()
+		}
+		wg.Wait()
+
+		assertBalance(t, wallet, Bitcoin(2000))
+	})
+
+	t.Run("opposite transfers do not deadlock and conserve funds", func(t *testing.T) {
+		a := &Wallet{balance: Bitcoin(500)}
+		b := &Wallet{balance: Bitcoin(500)}
+		const workers = 500
+
+		var wg sync.WaitGroup
+		wg.Add(2 * workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				Transfer(a, b, Bitcoin(3))
+			}()
+			go func() {
+				defer wg.Done()
+				Transfer(b, a, Bitcoin(2))
+			}()
+		}
+		wg.Wait()
+
+		if total := a.Balance() + b.Balance(); total != Bitcoin(1000) {
+			t.Errorf("expected total of %s got %s", Bitcoin(1000), total)
+		}
+		if a.Balance() < 0 || b.Balance() < 0 {
+			t.Errorf("a balance went negative: %s and %s", a.Balance(), b.Balance())
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Wallet is safe for concurrent use. The zero value is an empty wallet
+// with no overdraft. Set Now, OverdraftLimit and HoldTTL before sharing it.
+//
+// Every change to the balance is recorded in the wallet's ledger, and the
+// balance only ever changes by posting a ledger entry, so replaying the
+// ledger always gives Balance.
+type Wallet struct {
+	mu      sync.Mutex
+	balance Bitcoin
+	ledger  []Entry
+
+	holds    map[HoldID]hold
+	lastHold HoldID
+
+	// Now stamps ledger entries and times holds out. It defaults to time.Now.
+	Now func() time.Time
+
+	// OverdraftLimit is how far below zero the balance may go.
+	OverdraftLimit Bitcoin
+
+	// HoldTTL is how long holds last; zero means DefaultHoldTTL.
+	HoldTTL time.Duration
+
+	// id orders locks in Transfer. It is handed out on first use so the
+	// zero value stays usable.
+	id atomic.Uint64
+}
+
+var (
+	ErrInsufficientFunds = errors.New("cannot withdraw, insufficient funds")
+	ErrSameWallet        = errors.New("cannot transfer to the same wallet")
+)
+
+var lastWalletID atomic.Uint64
+
+// Deposit adds amount to the wallet. The amount must be positive, and a
+// deposit that would overflow the balance is refused.
+func (w *Wallet) Deposit(amount Bitcoin) error {
+	if err := validateAmount("deposit", amount); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	balance, err := w.balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	w.post(EntryDeposit, amount, balance)
+	return nil
+}
+
+func (w *Wallet) Balance() Bitcoin {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.balance
+}
+
+// Withdraw takes a positive amount out of the wallet. If the balance is
+// too low the error is an *InsufficientFundsError.
+func (w *Wallet) Withdraw(amount Bitcoin) error {
+	if err := validateAmount("withdraw", amount); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	balance, err := w.debit(amount)
+	if err != nil {
+		return err
+	}
+	w.post(EntryWithdrawal, amount, balance)
+	return nil
+}
+
+// debit works out the balance after taking amount out, without changing
+// anything. Holds and the overdraft limit count. It must be called with
+// w.mu held.
+func (w *Wallet) debit(amount Bitcoin) (Bitcoin, error) {
+	if available := w.available(); amount > available {
+		return 0, &InsufficientFundsError{Requested: amount, Available: available}
+	}
+	return w.balance.Sub(amount)
+}
+
+// Transfer moves amount from one wallet to another. Either both balances
+// change or neither does. Both locks are taken in the order of the
+// wallets' ids, so two transfers running in opposite directions between
+// the same wallets cannot deadlock.
+func Transfer(from, to *Wallet, amount Bitcoin) error {
+	if err := validateAmount("transfer", amount); err != nil {
+		return err
+	}
+	if from == to {
+		return ErrSameWallet
+	}
+
+	first, second := from, to
+	if to.lockID() < from.lockID() {
+		first, second = to, from
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	debited, err := from.debit(amount)
+	if err != nil {
+		return err
+	}
+	credited, err := to.balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	from.post(EntryTransferOut, amount, debited)
+	to.post(EntryTransferIn, amount, credited)
+	return nil
+}
+
+func (w *Wallet) lockID() uint64 {
+	if id := w.id.Load(); id != 0 {
+		return id
+	}
+	w.id.CompareAndSwap(0, lastWalletID.Add(1))
+	return w.id.Load()
+}
@@ -0,0 +1,47 @@
+package shapes
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNegativeDimension = errors.New("dimension must not be negative")
+	ErrInvalidDimension  = errors.New("dimension must be a finite number")
+	ErrDegenerateShape   = errors.New("shape is degenerate")
+	ErrUnknownShape      = errors.New("no shape registered with this name")
+	ErrDimensionCount    = errors.New("wrong number of dimensions for this shape")
+)
+
+// DimensionError reports one bad measurement of a shape: negative, not a
+// number, or zero so the shape collapses.
+type DimensionError struct {
+	Shape     string
+	Dimension string
+	Value     float64
+	Err       error
+}
+
+func (e *DimensionError) Error() string {
+	return fmt.Sprintf("%s %s of %g: %v", e.Shape, e.Dimension, e.Value, e.Err)
+}
+
+func (e *DimensionError) Unwrap() error {
+	return e.Err
+}
+
+// DegenerateError reports a shape whose measurements are each fine but
+// which together enclose no area, such as a flat triangle. It matches
+// ErrDegenerateShape with errors.Is.
+type DegenerateError struct {
+	Shape  string
+	Reason string
+}
+
+func (e *DegenerateError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.Shape, ErrDegenerateShape, e.Reason)
+}
+
+func (e *DegenerateError) Unwrap() error {
+	return ErrDegenerateShape
+}
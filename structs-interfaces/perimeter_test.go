@@ -1,28 +1,11 @@
-package main
+package shapes
 
 import (
+	"errors"
 	"math"
 	"testing"
 )
 
-type Rectangle struct {
-	Width  float64
-	Height float64
-}
-
-type Circle struct {
-	Radius float64
-}
-
-type Triangle struct {
-	Base   float64
-	Height float64
-}
-
-type Shape interface {
-	Area() float64
-}
-
 func TestPerimeter(t *testing.T) {
 	rectangle := Rectangle{10.0, 15.0}
 	got := Perimeter(rectangle)
@@ -31,6 +14,26 @@ func TestPerimeter(t *testing.T) {
 	if got != want {
 		t.Errorf("expected %.2f got %.2f", want, got)
 	}
+
+	perimeterTest := []struct {
+		name         string
+		shape        Shape
+		hasPerimeter float64
+	}{
+		{name: "Rectangle", shape: Rectangle{Width: 10.0, Height: 15.0}, hasPerimeter: 50.0},
+		{name: "Circle", shape: Circle{Radius: 10}, hasPerimeter: 62.83185307179586},
+		{name: "Triangle", shape: Triangle{A: 10, B: 10, C: 12}, hasPerimeter: 32},
+		{name: "Polygon", shape: Polygon{[]Point{{0, 0}, {4, 0}, {4, 3}}}, hasPerimeter: 12},
+	}
+
+	for _, tt := range perimeterTest {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.shape.Perimeter()
+			if got != tt.hasPerimeter {
+				t.Errorf("%#v expected %g got %g", tt.shape, tt.hasPerimeter, got)
+			}
+		})
+	}
 }
 
 func TestArea(t *testing.T) {
@@ -42,7 +45,11 @@ func TestArea(t *testing.T) {
 	}{
 		{name: "Rectangle", shape: Rectangle{Width: 10.0, Height: 15.0}, hasArea: 150.0},
 		{name: "Circle", shape: Circle{Radius: 10}, hasArea: 314.1592653589793},
-		{name: "Triangle", shape: Triangle{Base: 12, Height: 6}, hasArea: 36},
+		// base 12 with the two equal sides meeting 8 above it
+		{name: "Triangle", shape: Triangle{A: 10, B: 10, C: 12}, hasArea: 48},
+		{name: "Polygon", shape: Polygon{[]Point{{0, 0}, {4, 0}, {4, 3}, {0, 3}}}, hasArea: 12},
+		{name: "Polygon listed clockwise", shape: Polygon{[]Point{{0, 3}, {4, 3}, {4, 0}, {0, 0}}}, hasArea: 12},
+		{name: "Concave polygon", shape: Polygon{[]Point{{0, 0}, {4, 0}, {4, 4}, {2, 2}, {0, 4}}}, hasArea: 12},
 	}
 
 	for _, tt := range areaTest {
@@ -57,27 +64,74 @@ func TestArea(t *testing.T) {
 	}
 }
 
-func Perimeter(rectangle Rectangle) float64 {
-	return 2 * (rectangle.Width + rectangle.Height)
-}
-
-func (r Rectangle) Area() float64 {
-	return r.Width * r.Height
-}
-
-func (c Circle) Area() float64 {
-	return math.Pi * (c.Radius * c.Radius)
-}
+func TestValidate(t *testing.T) {
+	validTest := []Shape{
+		Rectangle{Width: 10, Height: 15},
+		Circle{Radius: 1},
+		Triangle{A: 3, B: 4, C: 5},
+		Polygon{[]Point{{0, 0}, {4, 0}, {4, 4}, {2, 2}, {0, 4}}},
+	}
 
-func (t Triangle) Area() float64 {
-	return 0.5 * t.Base * t.Height
-}
+	for _, shape := range validTest {
+		if err := shape.Validate(); err != nil {
+			t.Errorf("%#v expected to be valid got %v", shape, err)
+		}
+	}
 
-// Methods are very similar to functions but they are called by invoking them on an instance of a particular type.
-// Where you can just call functions wherever you like, such as Area(rectangle) you can only call methods on "things".
+	invalidTest := []struct {
+		name  string
+		shape Shape
+		want  error
+	}{
+		{name: "negative width", shape: Rectangle{Width: -1, Height: 2}, want: ErrNegativeDimension},
+		{name: "zero height", shape: Rectangle{Width: 1, Height: 0}, want: ErrDegenerateShape},
+		{name: "negative radius", shape: Circle{Radius: -3}, want: ErrNegativeDimension},
+		{name: "infinite radius", shape: Circle{Radius: math.Inf(1)}, want: ErrInvalidDimension},
+		{name: "NaN side", shape: Triangle{A: math.NaN(), B: 1, C: 1}, want: ErrInvalidDimension},
+		{name: "flat triangle", shape: Triangle{A: 1, B: 2, C: 3}, want: ErrDegenerateShape},
+		{name: "triangle that cannot close", shape: Triangle{A: 1, B: 1, C: 5}, want: ErrDegenerateShape},
+		{name: "polygon with two vertices", shape: Polygon{[]Point{{0, 0}, {1, 1}}}, want: ErrDegenerateShape},
+		{name: "polygon with a repeated vertex", shape: Polygon{[]Point{{0, 0}, {1, 0}, {1, 0}, {0, 1}}}, want: ErrDegenerateShape},
+		{name: "collinear polygon", shape: Polygon{[]Point{{0, 0}, {1, 1}, {2, 2}}}, want: ErrDegenerateShape},
+		{name: "self-intersecting polygon", shape: Polygon{[]Point{{0, 0}, {2, 2}, {2, 0}, {0, 2}}}, want: ErrDegenerateShape},
+		{name: "polygon with an infinite vertex", shape: Polygon{[]Point{{0, 0}, {math.Inf(-1), 0}, {0, 1}}}, want: ErrInvalidDimension},
+	}
 
-// Interfaces are a very powerful concept in statically typed languages like Go because they allow you to make
-// functions that can be used with different types and create highly-decoupled code whilst still maintaining type-safety.
+	for _, tt := range invalidTest {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.shape.Validate()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%#v expected %v got %v", tt.shape, tt.want, err)
+			}
+		})
+	}
 
-// If a type does not implement an interface, it means that type is not associated with the methods defined within the interface.
-// Interfaces can be used or implemented by different types, as expressed in this package.
+	t.Run("names the bad dimension", func(t *testing.T) {
+		err := Rectangle{Width: 2, Height: -4}.Validate()
+
+		var got *DimensionError
+		if !errors.As(err, &got) {
+			t.Fatalf("expected a *DimensionError got %v", err)
+		}
+		if got.Dimension != "height" || got.Value != -4 {
+			t.Errorf("unexpected dimension in %+v", got)
+		}
+		want := "rectangle height of -4: dimension must not be negative"
+		if err.Error() != want {
+			t.Errorf("expected %q got %q", want, err)
+		}
+	})
+
+	t.Run("explains a degenerate shape", func(t *testing.T) {
+		err := Triangle{A: 1, B: 2, C: 3}.Validate()
+
+		var got *DegenerateError
+		if !errors.As(err, &got) {
+			t.Fatalf("expected a *DegenerateError got %v", err)
+		}
+		want := "triangle: shape is degenerate: side of 3 is not shorter than the other two together"
+		if err.Error() != want {
+			t.Errorf("expected %q got %q", want, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package shapes
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ShapeFunc builds a shape from its dimensions, given in the order of the
+// shape's fields.
+type ShapeFunc func(dimensions ...float64) (Shape, error)
+
+// Registry looks shapes up by name so they can be built from plain data,
+// such as a line of a file reading "triangle 3 4 5".
+type Registry map[string]ShapeFunc
+
+// NewRegistry knows the shapes in this package: "rectangle" (width and
+// height), "circle" (radius), "triangle" (three sides) and "polygon" (the
+// x and y of each vertex in turn).
+func NewRegistry() Registry {
+	return Registry{
+		"rectangle": func(d ...float64) (Shape, error) {
+			if len(d) != 2 {
+				return nil, dimensionCount("rectangle", 2, len(d))
+			}
+			return Rectangle{d[0], d[1]}, nil
+		},
+		"circle": func(d ...float64) (Shape, error) {
+			if len(d) != 1 {
+				return nil, dimensionCount("circle", 1, len(d))
+			}
+			return Circle{d[0]}, nil
+		},
+		"triangle": func(d ...float64) (Shape, error) {
+			if len(d) != 3 {
+				return nil, dimensionCount("triangle", 3, len(d))
+			}
+			return Triangle{d[0], d[1], d[2]}, nil
+		},
+		"polygon": func(d ...float64) (Shape, error) {
+			if len(d)%2 != 0 {
+				return nil, fmt.Errorf("polygon: %w: got %d, want x and y pairs", ErrDimensionCount, len(d))
+			}
+			vertices := make([]Point, len(d)/2)
+			for i := range vertices {
+				vertices[i] = Point{d[2*i], d[2*i+1]}
+			}
+			return Polygon{vertices}, nil
+		},
+	}
+}
+
+func dimensionCount(shape string, want, got int) error {
+	return fmt.Errorf("%s: %w: got %d, want %d", shape, ErrDimensionCount, got, want)
+}
+
+// Register adds a shape, replacing any already registered as name.
+func (r Registry) Register(name string, build ShapeFunc) {
+	r[name] = build
+}
+
+// New builds the named shape and validates it, so a shape from New is
+// always safe to measure.
+func (r Registry) New(name string, dimensions ...float64) (Shape, error) {
+	build, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownShape, name)
+	}
+	shape, err := build(dimensions...)
+	if err != nil {
+		return nil, err
+	}
+	if err := shape.Validate(); err != nil {
+		return nil, err
+	}
+	return shape, nil
+}
+
+// Names lists the registered shapes alphabetically.
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,82 @@
+package shapes
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	t.Run("builds shapes by name", func(t *testing.T) {
+		buildTest := []struct {
+			name       string
+			dimensions []float64
+			want       Shape
+		}{
+			{name: "rectangle", dimensions: []float64{10, 15}, want: Rectangle{10, 15}},
+			{name: "circle", dimensions: []float64{10}, want: Circle{10}},
+			{name: "triangle", dimensions: []float64{3, 4, 5}, want: Triangle{3, 4, 5}},
+			{name: "polygon", dimensions: []float64{0, 0, 4, 0, 4, 3}, want: Polygon{[]Point{{0, 0}, {4, 0}, {4, 3}}}},
+		}
+
+		for _, tt := range buildTest {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := registry.New(tt.name, tt.dimensions...)
+				if err != nil {
+					t.Fatalf("expected no error got %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("expected %#v got %#v", tt.want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("refuses what it cannot build", func(t *testing.T) {
+		errorTest := []struct {
+			name       string
+			shape      string
+			dimensions []float64
+			want       error
+		}{
+			{name: "unknown shape", shape: "hexagon", dimensions: []float64{1}, want: ErrUnknownShape},
+			{name: "too few dimensions", shape: "rectangle", dimensions: []float64{1}, want: ErrDimensionCount},
+			{name: "odd polygon coordinates", shape: "polygon", dimensions: []float64{0, 0, 1}, want: ErrDimensionCount},
+			{name: "invalid shape", shape: "triangle", dimensions: []float64{1, 1, 5}, want: ErrDegenerateShape},
+		}
+
+		for _, tt := range errorTest {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := registry.New(tt.shape, tt.dimensions...)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("expected %v got %v", tt.want, err)
+				}
+			})
+		}
+	})
+
+	t.Run("registers new shapes", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register("square", func(d ...float64) (Shape, error) {
+			if len(d) != 1 {
+				return nil, ErrDimensionCount
+			}
+			return Rectangle{d[0], d[0]}, nil
+		})
+
+		got, err := registry.New("square", 3)
+		if err != nil {
+			t.Fatalf("expected no error got %v", err)
+		}
+		if got.Area() != 9 {
+			t.Errorf("expected area 9 got %g", got.Area())
+		}
+
+		want := []string{"circle", "polygon", "rectangle", "square", "triangle"}
+		if names := registry.Names(); !reflect.DeepEqual(names, want) {
+			t.Errorf("expected %v got %v", want, names)
+		}
+	})
+}
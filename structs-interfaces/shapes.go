@@ -0,0 +1,101 @@
+package shapes
+
+import (
+	"math"
+)
+
+// Shape is anything with an area and a perimeter. Area and Perimeter
+// assume the shape is valid and do not check: an impossible Triangle gives
+// NaN and a negative Rectangle a negative area. Call Validate first, or
+// build shapes through a Registry, which does.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	Validate() error
+}
+
+type Rectangle struct {
+	Width  float64
+	Height float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+// Triangle is given by the lengths of its three sides.
+type Triangle struct {
+	A, B, C float64
+}
+
+type Point struct {
+	X, Y float64
+}
+
+// Polygon is a simple polygon given by its vertices in order, clockwise or
+// anticlockwise. The last vertex joins back to the first.
+type Polygon struct {
+	Vertices []Point
+}
+
+// Perimeter works for any Shape now that every Shape has a perimeter.
+func Perimeter(shape Shape) float64 {
+	return shape.Perimeter()
+}
+
+func (r Rectangle) Area() float64 {
+	return r.Width * r.Height
+}
+
+func (r Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+func (c Circle) Area() float64 {
+	return math.Pi * (c.Radius * c.Radius)
+}
+
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+// Area uses Heron's formula, which needs only the sides.
+func (t Triangle) Area() float64 {
+	s := t.Perimeter() / 2
+	return math.Sqrt(s * (s - t.A) * (s - t.B) * (s - t.C))
+}
+
+func (t Triangle) Perimeter() float64 {
+	return t.A + t.B + t.C
+}
+
+// Area uses the shoelace formula.
+func (p Polygon) Area() float64 {
+	var sum float64
+	for i, a := range p.Vertices {
+		b := p.Vertices[(i+1)%len(p.Vertices)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return math.Abs(sum) / 2
+}
+
+func (p Polygon) Perimeter() float64 {
+	var sum float64
+	for i, a := range p.Vertices {
+		sum += distance(a, p.Vertices[(i+1)%len(p.Vertices)])
+	}
+	return sum
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// Methods are very similar to functions but they are called by invoking them on an instance of a particular type.
+// Where you can just call functions wherever you like, such as Area(rectangle) you can only call methods on "things".
+
+// Interfaces are a very powerful concept in statically typed languages like Go because they allow you to make
+// functions that can be used with different types and create highly-decoupled code whilst still maintaining type-safety.
+
+// If a type does not implement an interface, it means that type is not associated with the methods defined within the interface.
+// Interfaces can be used or implemented by different types, as expressed in this package.
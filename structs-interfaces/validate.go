@@ -0,0 +1,115 @@
+package shapes
+
+import (
+	"fmt"
+	"math"
+)
+
+// checkLength accepts only a positive, finite length.
+func checkLength(shape, dimension string, value float64) error {
+	var err error
+	switch {
+	case math.IsNaN(value), math.IsInf(value, 0):
+		err = ErrInvalidDimension
+	case value < 0:
+		err = ErrNegativeDimension
+	case value == 0:
+		err = ErrDegenerateShape
+	default:
+		return nil
+	}
+	return &DimensionError{shape, dimension, value, err}
+}
+
+func (r Rectangle) Validate() error {
+	if err := checkLength("rectangle", "width", r.Width); err != nil {
+		return err
+	}
+	return checkLength("rectangle", "height", r.Height)
+}
+
+func (c Circle) Validate() error {
+	return checkLength("circle", "radius", c.Radius)
+}
+
+// Validate also checks the triangle inequality: each side must be shorter
+// than the other two together, or the triangle is flat or cannot close.
+func (t Triangle) Validate() error {
+	for _, side := range []struct {
+		name  string
+		value float64
+	}{{"side a", t.A}, {"side b", t.B}, {"side c", t.C}} {
+		if err := checkLength("triangle", side.name, side.value); err != nil {
+			return err
+		}
+	}
+
+	longest := max(t.A, t.B, t.C)
+	if longest >= t.Perimeter()-longest {
+		return &DegenerateError{"triangle", fmt.Sprintf("side of %g is not shorter than the other two together", longest)}
+	}
+	return nil
+}
+
+// Validate checks the polygon has at least three vertices, no two
+// consecutive ones the same, no edges that cross, and some area.
+func (p Polygon) Validate() error {
+	n := len(p.Vertices)
+	if n < 3 {
+		return &DegenerateError{"polygon", fmt.Sprintf("%d vertices, need at least 3", n)}
+	}
+
+	for i, v := range p.Vertices {
+		for _, coord := range []float64{v.X, v.Y} {
+			if math.IsNaN(coord) || math.IsInf(coord, 0) {
+				return &DimensionError{"polygon", fmt.Sprintf("vertex %d", i), coord, ErrInvalidDimension}
+			}
+		}
+		if v == p.Vertices[(i+1)%n] {
+			return &DegenerateError{"polygon", fmt.Sprintf("vertices %d and %d are the same point", i, (i+1)%n)}
+		}
+	}
+
+	// compare every pair of edges that do not share a vertex
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if segmentsIntersect(p.Vertices[i], p.Vertices[i+1], p.Vertices[j], p.Vertices[(j+1)%n]) {
+				return &DegenerateError{"polygon", fmt.Sprintf("edges %d and %d cross", i, j)}
+			}
+		}
+	}
+
+	if p.Area() == 0 {
+		return &DegenerateError{"polygon", "vertices are collinear"}
+	}
+	return nil
+}
+
+// segmentsIntersect reports whether segments ab and cd touch or cross.
+func segmentsIntersect(a, b, c, d Point) bool {
+	d1, d2 := orientation(c, d, a), orientation(c, d, b)
+	d3, d4 := orientation(a, b, c), orientation(a, b, d)
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	return d1 == 0 && onSegment(c, d, a) ||
+		d2 == 0 && onSegment(c, d, b) ||
+		d3 == 0 && onSegment(a, b, c) ||
+		d4 == 0 && onSegment(a, b, d)
+}
+
+// orientation is positive if p is left of the line through a and b,
+// negative if right and zero if on it.
+func orientation(a, b, p Point) float64 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+// onSegment reports whether p, already known to be on the line through a
+// and b, lies between them.
+func onSegment(a, b, p Point) bool {
+	return min(a.X, b.X) <= p.X && p.X <= max(a.X, b.X) &&
+		min(a.Y, b.Y) <= p.Y && p.Y <= max(a.Y, b.Y)
+}